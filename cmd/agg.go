@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var aggregationNames []string
+
+// aggregation reduces the matches of a query, or the numbers among them, to a single value
+type aggregation func(matches []interface{}, numbers []float64) interface{}
+
+// aggregations maps each --agg name to its implementation. count counts every match; the
+// others work on numbers, and of no numbers are 0 for sum and null otherwise.
+var aggregations = map[string]aggregation{
+	"sum": func(matches []interface{}, numbers []float64) interface{} {
+		sum := 0.0
+		for _, n := range numbers {
+			sum += n
+		}
+		return sum
+	},
+	"avg": func(matches []interface{}, numbers []float64) interface{} {
+		if len(numbers) == 0 {
+			return nil
+		}
+		sum := 0.0
+		for _, n := range numbers {
+			sum += n
+		}
+		return sum / float64(len(numbers))
+	},
+	"min": func(matches []interface{}, numbers []float64) interface{} {
+		if len(numbers) == 0 {
+			return nil
+		}
+		min := math.Inf(1)
+		for _, n := range numbers {
+			min = math.Min(min, n)
+		}
+		return min
+	},
+	"max": func(matches []interface{}, numbers []float64) interface{} {
+		if len(numbers) == 0 {
+			return nil
+		}
+		max := math.Inf(-1)
+		for _, n := range numbers {
+			max = math.Max(max, n)
+		}
+		return max
+	},
+	"count": func(matches []interface{}, numbers []float64) interface{} {
+		return float64(len(matches))
+	},
+}
+
+func init() {
+	readCmd.Flags().StringSliceVar(&aggregationNames, "agg", nil, "Reduce matches with count, or numeric matches with sum, avg, min or max; several give an object of results")
+	readCmd.RegisterFlagCompletionFunc("agg", aggregationCompletion)
+}
+
+// aggregationCompletion suggests the --agg names
+func aggregationCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := make([]string, 0, len(aggregations))
+	for name := range aggregations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// validateAggregations checks that every name is a known aggregation
+func validateAggregations(names []string) error {
+	for _, name := range names {
+		if _, ok := aggregations[name]; !ok {
+			return fmt.Errorf("unsupported aggregation: %s (use sum, avg, min, max or count)", name)
+		}
+	}
+	return nil
+}
+
+// aggregateQuery wraps a query so its matches are reduced with the named aggregations
+func aggregateQuery(query documentQuery, names []string) (documentQuery, error) {
+	if err := validateAggregations(names); err != nil {
+		return nil, err
+	}
+	return func(jsonData interface{}) (interface{}, error) {
+		result, err := query(jsonData)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(result, names)
+	}, nil
+}
+
+// aggregate applies the named aggregations to a result, which is either an array of matches
+// or a single match. count takes any match; for the numeric aggregations nulls are skipped
+// and any other non-number is an error. One name gives its value and several give an object
+// keyed by name.
+func aggregate(result interface{}, names []string) (interface{}, error) {
+	matches, ok := result.([]interface{})
+	if !ok {
+		matches = []interface{}{result}
+	}
+
+	numbers := make([]float64, 0, len(matches))
+	for i, match := range matches {
+		if match == nil || isCountOnly(names) {
+			continue
+		}
+		n, ok := numberValue(match)
+		if !ok {
+			return nil, fmt.Errorf("Error aggregating with %s: match %d is not a number (got %s)", strings.Join(names, ","), i, jsonTypeName(match))
+		}
+		numbers = append(numbers, n)
+	}
+
+	if len(names) == 1 {
+		return aggregations[names[0]](matches, numbers), nil
+	}
+	results := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		results[name] = aggregations[name](matches, numbers)
+	}
+	return results, nil
+}
+
+// numberValue returns the value of a decoded number of any of the types the decoders produce
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	anonymizePaths []string
+	anonymizeKey   string
+)
+
+// anonymizeCmd represents the anonymize command
+var anonymizeCmd = &cobra.Command{
+	Use:   "anonymize",
+	Short: "Replace values with deterministic tokens of the same shape",
+	Long: `Replace the values the --paths expressions match with tokens derived from a keyed hash
+of each value. Tokens keep the shape of the original: letters stay letters of the same case,
+digits stay digits and other characters are kept, so jane.doe@example.com might become
+qfzs.rwk@hbmolnp.xir, and numbers keep their sign and number of digits. The same value
+always gives the same token for the same --key, so identifiers still join across files.
+Objects and arrays are anonymized leaf by leaf; booleans and nulls are left as they are.
+Prints the result, or rewrites the file with -i:
+
+  $ mycli anonymize -f users.json --paths '$[*].email,$[*].id' --key "$ANON_KEY"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPaths := []string{}
+		for _, list := range anonymizePaths {
+			jsonPaths = append(jsonPaths, splitPathList(list)...)
+		}
+
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		locations, err := locatePaths(jsonData, jsonPaths)
+		if err != nil {
+			return err
+		}
+
+		for _, loc := range outermostLocations(locations) {
+			value, _ := getAt(jsonData, loc)
+			jsonData = setAt(jsonData, loc, anonymizeValue(value, []byte(anonymizeKey)))
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(anonymizeCmd)
+
+	addEditFlags(anonymizeCmd)
+	anonymizeCmd.Flags().StringArrayVar(&anonymizePaths, "paths", nil, "Comma-separated JSONPaths whose values to anonymize (repeatable)")
+	anonymizeCmd.MarkFlagRequired("paths")
+	anonymizeCmd.Flags().StringVar(&anonymizeKey, "key", "", "Secret key for the hash; without one, tokens of guessable values can be reversed")
+}
+
+// anonymizeValue replaces strings and numbers, including those inside objects and arrays,
+// with tokens of the same shape
+func anonymizeValue(value interface{}, key []byte) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			v[name] = anonymizeValue(child, key)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = anonymizeValue(child, key)
+		}
+		return v
+	case string:
+		return anonymizeText(v, "string:"+v, key)
+	case float64:
+		return anonymizeNumber(v, key)
+	default:
+		return v
+	}
+}
+
+// anonymizeNumber replaces each digit of a number, keeping its sign and decimal point. The
+// leading digit, and the last of a fraction, stay non-zero so no digits are lost.
+func anonymizeNumber(number float64, key []byte) interface{} {
+	if math.IsInf(number, 0) || math.IsNaN(number) {
+		return number
+	}
+	text := strconv.FormatFloat(number, 'f', -1, 64)
+	digits := strings.TrimPrefix(text, "-")
+	token := anonymizeText(digits, "number:"+text, key)
+	if digits[0] != '0' && token[0] == '0' {
+		token = "1" + token[1:]
+	}
+	if strings.Contains(token, ".") && strings.HasSuffix(token, "0") {
+		token = token[:len(token)-1] + "1"
+	}
+	result, err := strconv.ParseFloat(strings.TrimSuffix(text, digits)+token, 64)
+	if err != nil {
+		return number
+	}
+	return result
+}
+
+// anonymizeText replaces each letter and digit of text with one of the same kind, picked by
+// an HMAC of seed, so equal seeds always give equal tokens
+func anonymizeText(text string, seed string, key []byte) string {
+	stream := hashStream(seed, key)
+	var b strings.Builder
+	for i, r := range []rune(text) {
+		n := stream(i)
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteByte(byte('0' + n%10))
+		case unicode.IsUpper(r):
+			b.WriteByte(byte('A' + n%26))
+		case unicode.IsLetter(r):
+			b.WriteByte(byte('a' + n%26))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// hashStream returns a function giving a pseudorandom number for each position, taken from
+// HMAC-SHA256 blocks of the seed and a block counter
+func hashStream(seed string, key []byte) func(position int) uint32 {
+	blocks := [][]byte{}
+	return func(position int) uint32 {
+		// Each 32-byte block holds eight numbers
+		for len(blocks) <= position/8 {
+			mac := hmac.New(sha256.New, key)
+			fmt.Fprintf(mac, "%d:%s", len(blocks), seed)
+			blocks = append(blocks, mac.Sum(nil))
+		}
+		block := blocks[position/8]
+		return binary.BigEndian.Uint32(block[position%8*4:])
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	appendString bool
+	insertAt     int
+)
+
+// newArrayEditCommand builds the append and insert commands; position picks where the value
+// goes in an array of the given length
+func newArrayEditCommand(name string, short string, example string, position func(length int) (int, error)) *cobra.Command {
+	command := &cobra.Command{
+		Use:   name + " <jsonpath> <value>",
+		Short: short,
+		Long: short + `, printing the modified
+document or, with -i, rewriting the file. The value is parsed as JSON unless --string is
+given. A path of plain keys that matches nothing is created as an array holding the value:
+
+  $ mycli ` + example,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: jsonPathCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonPath := args[0]
+			var value interface{} = args[1]
+			if !appendString {
+				if err := json.Unmarshal([]byte(args[1]), &value); err != nil {
+					return fmt.Errorf("Error parsing value as JSON: %v (use --string to add it as text)", err)
+				}
+			}
+
+			jsonData, err := loadEditDocument()
+			if err != nil {
+				return err
+			}
+			if jsonData, err = addToArrays(jsonData, jsonPath, value, position); err != nil {
+				return fmt.Errorf("Error adding to %s: %v", jsonPath, err)
+			}
+			return saveEditedDocument(jsonData)
+		},
+	}
+	addEditFlags(command)
+	command.Flags().BoolVar(&appendString, "string", false, "Add the value as a string instead of parsing it as JSON")
+	return command
+}
+
+func init() {
+	rootCmd.AddCommand(newArrayEditCommand("append", "Add a value to the end of the arrays a path matches",
+		`append -f order.json '$.items' '{"id": 9}'`,
+		func(length int) (int, error) {
+			return length, nil
+		}))
+
+	insertCmd := newArrayEditCommand("insert", "Insert a value into the arrays a path matches",
+		`insert -f order.json '$.items' '{"id": 9}' --at 0`,
+		func(length int) (int, error) {
+			return insertIndex(insertAt, length)
+		})
+	insertCmd.Flags().IntVar(&insertAt, "at", 0, "Index to insert the value before; negative indices count from the end")
+	insertCmd.MarkFlagRequired("at")
+	rootCmd.AddCommand(insertCmd)
+}
+
+// insertIndex resolves an --at index, which counts from the end when negative, for an
+// array of the given length
+func insertIndex(at int, length int) (int, error) {
+	index := at
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index > length {
+		return 0, fmt.Errorf("--at %d is out of range for an array of length %d", at, length)
+	}
+	return index, nil
+}
+
+// addToArrays adds a copy of the value to every array a path matches, at the index position
+// picks for it
+func addToArrays(jsonData interface{}, jsonPath string, value interface{}, position func(length int) (int, error)) (interface{}, error) {
+	locations, err := locateJSONPath(jsonData, jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locations) == 0 {
+		loc, ok := singularLocation(jsonPath)
+		if !ok {
+			return nil, fmt.Errorf("the path matched nothing, and only paths of plain keys and indices can be created")
+		}
+		if _, err := position(0); err != nil {
+			return nil, err
+		}
+		return createAt(jsonData, loc, []interface{}{value}, false)
+	}
+
+	for _, loc := range locations {
+		node, _ := getAt(jsonData, loc)
+		array, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is %s, not an array", loc, jsonTypeName(node))
+		}
+		index, err := position(len(array))
+		if err != nil {
+			return nil, err
+		}
+		if jsonData, err = createAt(jsonData, appendLocation(loc, index), copyValue(value), true); err != nil {
+			return nil, err
+		}
+	}
+	return jsonData, nil
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var editsPath string
+
+// editStep is one entry of an edit spec
+type editStep struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+	// To is the destination of move and copy
+	To string `json:"to"`
+	// Name is the new key for rename
+	Name string `json:"name"`
+	// At is the index insert adds the value before
+	At *int `json:"at"`
+	// Force lets rename replace an existing member
+	Force bool `json:"force"`
+	// hasValue tells an explicit null value from a missing one
+	hasValue bool
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a list of edits from a spec file",
+	Long: `Apply the edits listed in a JSON or YAML spec file, in order, and print the modified
+document or rewrite the file with -i. Each edit names an op and a path and works like the
+command of the same name: set and append take a value, insert a value and an at index,
+rename a name (and optionally force), move and copy a to path, and delete removes every
+match. If any edit fails, the error is reported and nothing is changed:
+
+  $ cat edits.yaml
+  - {op: set, path: $.spec.replicas, value: 3}
+  - {op: rename, path: $.metadata.labels.app, name: app.kubernetes.io/name}
+  - {op: delete, path: $.status}
+  $ mycli apply -f deployment.json --edits edits.yaml -i`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := loadEditSpec(editsPath)
+		if err != nil {
+			return fmt.Errorf("%s: %v", editsPath, err)
+		}
+		steps, err := parseEditSteps(spec)
+		if err != nil {
+			return err
+		}
+
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		jsonData = copyValue(jsonData)
+		for i, step := range steps {
+			if jsonData, err = applyEditStep(jsonData, step); err != nil {
+				return fmt.Errorf("Error applying edit %d (%s %s): %v", i, step.Op, step.Path, err)
+			}
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	addEditFlags(applyCmd)
+	applyCmd.Flags().StringVar(&editsPath, "edits", "", "JSON or YAML file listing the edits to apply")
+	applyCmd.MarkFlagRequired("edits")
+	applyCmd.MarkFlagFilename("edits", "json", "yaml", "yml")
+}
+
+// loadEditSpec reads an edit spec, as YAML when the file is named .yaml or .yml and
+// otherwise like any other input file
+func loadEditSpec(path string) (interface{}, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading file: %v", err)
+		}
+		return parseYAML(data)
+	default:
+		return loadFile(path)
+	}
+}
+
+// parseYAML decodes the first document of a YAML edit spec into the generic JSON tree
+func parseYAML(data []byte) (interface{}, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("Error parsing YAML: %v", err)
+	}
+	return jsonValueFromYAML(value), nil
+}
+
+// jsonValueFromYAML converts what the YAML decoder produces into JSON's types: numbers
+// become float64, timestamps RFC 3339 strings (dates alone when there is no time of day), and non-string keys their text
+func jsonValueFromYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = jsonValueFromYAML(child)
+		}
+		return v
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			object[fmt.Sprint(key)] = jsonValueFromYAML(child)
+		}
+		return object
+	case []interface{}:
+		for i, child := range v {
+			v[i] = jsonValueFromYAML(child)
+		}
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case time.Time:
+		if v.Equal(v.Truncate(24*time.Hour)) && v.Location() == time.UTC {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	default:
+		return v
+	}
+}
+
+// parseEditSteps checks that a spec is a list of edits, each with the fields its op needs
+// and no others
+func parseEditSteps(spec interface{}) ([]editStep, error) {
+	items, ok := spec.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Error parsing edits: expected a list of edits, got %s", jsonTypeName(spec))
+	}
+
+	steps := make([]editStep, len(items))
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Error parsing edits: edit %d is %s, not an object", i, jsonTypeName(item))
+		}
+		encoded, _ := json.Marshal(object)
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&steps[i]); err != nil {
+			return nil, fmt.Errorf("Error parsing edits: edit %d: %v", i, err)
+		}
+		_, steps[i].hasValue = object["value"]
+
+		step := steps[i]
+		var missing string
+		switch step.Op {
+		case "set", "append":
+			if !step.hasValue {
+				missing = "value"
+			}
+		case "insert":
+			if !step.hasValue {
+				missing = "value"
+			} else if step.At == nil {
+				missing = "at"
+			}
+		case "rename":
+			if step.Name == "" {
+				missing = "name"
+			}
+		case "move", "copy":
+			if step.To == "" {
+				missing = "to"
+			}
+		case "delete":
+		case "":
+			return nil, fmt.Errorf("Error parsing edits: edit %d has no op", i)
+		default:
+			return nil, fmt.Errorf("Error parsing edits: edit %d has unknown op %q (use set, delete, rename, move, copy, append or insert)", i, step.Op)
+		}
+		if step.Path == "" {
+			missing = "path"
+		}
+		if missing != "" {
+			return nil, fmt.Errorf("Error parsing edits: %s edit %d has no %s", step.Op, i, missing)
+		}
+	}
+	return steps, nil
+}
+
+// applyEditStep applies a single edit, returning the possibly new root
+func applyEditStep(jsonData interface{}, step editStep) (interface{}, error) {
+	switch step.Op {
+	case "set":
+		return setPath(jsonData, step.Path, copyValue(step.Value))
+	case "delete":
+		locations, err := locateJSONPath(jsonData, step.Path)
+		if err != nil {
+			return nil, jsonPathError(step.Path, err)
+		}
+		for _, loc := range locations {
+			if len(loc) == 0 {
+				return nil, fmt.Errorf("Cannot delete the document root")
+			}
+		}
+		return removeLocations(jsonData, locations), nil
+	case "rename":
+		return jsonData, renameMembers(jsonData, step.Path, step.Name, step.Force)
+	case "move", "copy":
+		return relocate(jsonData, step.Path, step.To, step.Op == "move")
+	case "append":
+		return addToArrays(jsonData, step.Path, step.Value, func(length int) (int, error) {
+			return length, nil
+		})
+	default:
+		return addToArrays(jsonData, step.Path, step.Value, func(length int) (int, error) {
+			return insertIndex(*step.At, length)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// memberHandler receives each decoded document found in an archive along with its member name
+type memberHandler func(name string, jsonData interface{}) error
+
+// isArchivePath reports whether the --file value names a zip or tar archive, as a local
+// file or as an HTTP(S) URL
+func isArchivePath(path string) bool {
+	lower := strings.ToLower(archiveName(path))
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveName returns the part of a --file value that names the archive: the path of a URL,
+// without its query string, or the local path itself
+func archiveName(path string) string {
+	if !isHTTPURL(path) {
+		return path
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return u.Path
+}
+
+// readArchive decodes every member of a zip or tar(.gz) archive whose format is recognized and
+// passes it to the handler. Members that fail to decode or query are reported on stderr as they
+// are met, and make the whole read fail once the other members have been handled.
+func readArchive(path string, handle memberHandler) error {
+	var data []byte
+	var err error
+	if isHTTPURL(path) {
+		if data, err = fetchURL(path); err != nil {
+			return err
+		}
+	} else if data, err = os.ReadFile(path); err != nil {
+		return fmt.Errorf("Error reading file: %v", err)
+	}
+
+	name := archiveName(path)
+	if strings.HasSuffix(strings.ToLower(name), ".zip") {
+		return readZip(data, handle)
+	}
+	return readTar(name, data, handle)
+}
+
+// readZip walks the members of a zip archive
+func readZip(data []byte, handle memberHandler) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("Error reading archive: %v", err)
+	}
+
+	failed := 0
+	for _, member := range archive.File {
+		if member.FileInfo().IsDir() || formatForPath(member.Name) == "" {
+			continue
+		}
+
+		reader, err := member.Open()
+		if err != nil {
+			return fmt.Errorf("Error reading archive: %v", err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("Error reading archive: %v", err)
+		}
+
+		if !handleArchiveMember(member.Name, content, handle) {
+			failed++
+		}
+	}
+	return failedMembersError(failed)
+}
+
+// readTar walks the members of a tar archive, decompressing it first if gzipped
+func readTar(path string, data []byte, handle memberHandler) error {
+	var reader io.Reader = bytes.NewReader(data)
+
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("Error reading archive: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	archive := tar.NewReader(reader)
+	failed := 0
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return failedMembersError(failed)
+		}
+		if err != nil {
+			return fmt.Errorf("Error reading archive: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg || formatForPath(header.Name) == "" {
+			continue
+		}
+
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return fmt.Errorf("Error reading archive: %v", err)
+		}
+
+		if !handleArchiveMember(header.Name, content, handle) {
+			failed++
+		}
+	}
+}
+
+// handleArchiveMember decodes one member and passes it to the handler, reporting failures on
+// stderr. It returns whether the member was handled successfully.
+func handleArchiveMember(name string, content []byte, handle memberHandler) bool {
+	decoder, err := decoderForPath(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return false
+	}
+
+	jsonData, err := decodeDocument(decoder, content)
+	if err == nil {
+		err = handle(name, jsonData)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return false
+	}
+	return true
+}
+
+// failedMembersError summarizes the members that could not be read, or returns nil if none failed
+func failedMembersError(failed int) error {
+	switch failed {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("Error reading archive: 1 member failed")
+	default:
+		return fmt.Errorf("Error reading archive: %d members failed", failed)
+	}
+}
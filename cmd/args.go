@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	queryArgs     []string
+	queryJSONArgs []string
+)
+
+// argName matches the names usable as $name or $(name) in query expressions
+var argName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+
+func init() {
+	readCmd.Flags().StringArrayVar(&queryArgs, "arg", nil, "Set a query variable to a string, as --arg name value or --arg name=value, usable as $name or $(name) (repeatable)")
+	readCmd.Flags().StringArrayVar(&queryJSONArgs, "argjson", nil, "Set a query variable to a JSON value, as --argjson name json or --argjson name=json (repeatable)")
+}
+
+// joinArgPairs rewrites jq's two-argument forms, --arg name value and --argjson name json, into
+// the name=value form the flags parse, since a flag only takes a single value
+func joinArgPairs(args []string) []string {
+	result := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(result, args[i:]...)
+		}
+		flag, name, attached := strings.Cut(arg, "=")
+		if flag != "--arg" && flag != "--argjson" {
+			result = append(result, arg)
+			continue
+		}
+
+		switch {
+		case attached && argName.FindString(name) == name && i+1 < len(args):
+			// --arg=name value
+			result = append(result, arg+"="+args[i+1])
+			i++
+		case !attached && i+2 < len(args) && argName.FindString(args[i+1]) == args[i+1] && args[i+1] != "":
+			// --arg name value
+			result = append(result, arg, args[i+1]+"="+args[i+2])
+			i += 2
+		default:
+			result = append(result, arg)
+		}
+	}
+	return result
+}
+
+// queryVariables collects the --arg and --argjson values by name
+func queryVariables() (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	for _, arg := range queryArgs {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || argName.FindString(name) != name {
+			return nil, fmt.Errorf("invalid --arg %q: expected a name and value, as in --arg env prod", arg)
+		}
+		variables[name] = value
+	}
+	for _, arg := range queryJSONArgs {
+		name, text, ok := strings.Cut(arg, "=")
+		if !ok || argName.FindString(name) != name {
+			return nil, fmt.Errorf("invalid --argjson %q: expected a name and JSON value, as in --argjson limit 10", arg)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil, fmt.Errorf("invalid --argjson %s: %v", name, err)
+		}
+		variables[name] = value
+	}
+	return variables, nil
+}
+
+// variableNames returns the variable names in sorted order
+func variableNames(variables map[string]interface{}) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// substituteVariables replaces $name and $(name) outside quoted strings with the variable's
+// value written as a literal of the query language. After a '.', the value becomes a key.
+func substituteVariables(expression string, language string, variables map[string]interface{}) (string, error) {
+	if len(variables) == 0 {
+		return expression, nil
+	}
+
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(expression) {
+				b.WriteByte(c)
+				i++
+				c = expression[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || (c == '`' && language == "jmespath"):
+			quote = c
+		case c == '$':
+			name, length := variableReference(expression[i+1:])
+			value, ok := variables[name]
+			if !ok {
+				break
+			}
+
+			// A '.' before the variable (but not '..') makes it a member name
+			text := b.String()
+			asKey := strings.HasSuffix(text, ".") && !strings.HasSuffix(text, "..")
+			literal, err := variableLiteral(name, value, language, asKey)
+			if err != nil {
+				return "", err
+			}
+			if asKey && language == "jsonpath" {
+				b.Reset()
+				b.WriteString(strings.TrimSuffix(text, "."))
+			}
+			b.WriteString(literal)
+			i += length
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// variableReference reads the name after a '$', in either the $name or $(name) form,
+// returning the name and how many bytes it spans
+func variableReference(rest string) (string, int) {
+	if strings.HasPrefix(rest, "(") {
+		if end := strings.IndexByte(rest, ')'); end > 0 {
+			name := strings.TrimSpace(rest[1:end])
+			return name, end + 1
+		}
+		return "", 0
+	}
+	name := argName.FindString(rest)
+	return name, len(name)
+}
+
+// variableLiteral writes a variable's value as a JSONPath or JMESPath literal or member name
+func variableLiteral(name string, value interface{}, language string, asKey bool) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case asKey:
+		if _, ok := value.(string); !ok {
+			return "", fmt.Errorf("variable %s is used as a key but is not a string", name)
+		}
+		if language == "jsonpath" {
+			return "[" + string(encoded) + "]", nil
+		}
+		// JMESPath quoted identifiers are JSON strings
+		return string(encoded), nil
+	case language == "jmespath":
+		return "`" + string(encoded) + "`", nil
+	default:
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return "", fmt.Errorf("variable %s holds an %s, which cannot be used in a JSONPath", name, jsonTypeName(value))
+		}
+		return string(encoded), nil
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	decodeBase64Paths []string
+	parseDecodedJSON  bool
+)
+
+func init() {
+	readCmd.Flags().StringArrayVar(&decodeBase64Paths, "decode-base64", nil, "JSONPath of string fields to base64-decode before display, e.g. '$.data.*' (repeatable)")
+	readCmd.Flags().BoolVar(&parseDecodedJSON, "parse-decoded", false, "Parse base64-decoded fields as JSON when they contain valid JSON")
+}
+
+// decodeBase64Fields decodes the string fields selected by --decode-base64 in the result
+func decodeBase64Fields(data interface{}) (interface{}, error) {
+	for _, path := range decodeBase64Paths {
+		locations, err := locateJSONPath(data, path)
+		if err != nil {
+			return nil, fmt.Errorf("Error in --decode-base64: %v", err)
+		}
+		for _, loc := range locations {
+			value, _ := getAt(data, loc)
+			encoded, ok := value.(string)
+			if !ok {
+				continue
+			}
+			decoded, ok := decodeBase64String(encoded)
+			if !ok {
+				continue
+			}
+			data = setAt(data, loc, decodedValue(decoded))
+		}
+	}
+	return data, nil
+}
+
+// decodeBase64String accepts standard and URL-safe alphabets, with or without padding
+func decodeBase64String(s string) ([]byte, bool) {
+	s = strings.TrimSpace(s)
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if decoded, err := encoding.DecodeString(s); err == nil {
+			return decoded, true
+		}
+	}
+	return nil, false
+}
+
+// decodedValue turns decoded bytes into a display value, keeping binary data encoded
+func decodedValue(decoded []byte) interface{} {
+	if parseDecodedJSON {
+		var parsed interface{}
+		if err := json.Unmarshal(decoded, &parsed); err == nil {
+			return parsed
+		}
+	}
+	if !utf8.Valid(decoded) {
+		return base64.StdEncoding.EncodeToString(decoded)
+	}
+	return string(decoded)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+var copyOutput bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&copyOutput, "copy", false, "Also copy the printed result to the system clipboard")
+}
+
+// clipboardCommands lists the tools tried, in order, to write the clipboard on each platform
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	default:
+		commands := [][]string{}
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			commands = append(commands, []string{"wl-copy"})
+		}
+		return append(commands,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+}
+
+// writeClipboard places data on the system clipboard using the first available tool
+func writeClipboard(data []byte) error {
+	for _, args := range clipboardCommands() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+
+		command := exec.Command(path, args[1:]...)
+		command.Stdin = bytes.NewReader(data)
+		if output, err := command.CombinedOutput(); err != nil {
+			return fmt.Errorf("Error copying to clipboard: %v %s", err, bytes.TrimSpace(output))
+		}
+		return nil
+	}
+	return fmt.Errorf("Error copying to clipboard: no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	colorMode  string
+	colorTheme string
+)
+
+// colorScheme holds the ANSI SGR parameters used for each kind of JSON token
+type colorScheme struct {
+	Key    string
+	String string
+	Number string
+	Bool   string
+	Null   string
+}
+
+// colorThemes are the built-in schemes selectable with --theme
+var colorThemes = map[string]colorScheme{
+	"default": {Key: "34;1", String: "32", Number: "36", Bool: "33", Null: "90"},
+	"jq":      {Key: "34;1", String: "32", Number: "39", Bool: "39", Null: "1;30"},
+	"bright":  {Key: "94;1", String: "92", Number: "96", Bool: "93", Null: "95"},
+	"mono":    {Key: "1", String: "", Number: "", Bool: "", Null: "2"},
+}
+
+func init() {
+	resultFlags.StringVar(&colorMode, "color", "auto", "Colorize JSON output: auto, always or never")
+	resultFlags.StringVar(&colorTheme, "theme", "default", "Color theme for JSON output; override with MYCLI_COLORS=key=34:string=32:...")
+
+	resultFlagCompletions["color"] = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"auto", "always", "never"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	resultFlagCompletions["theme"] = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		themes := make([]string, 0, len(colorThemes))
+		for name := range colorThemes {
+			themes = append(themes, name)
+		}
+		sort.Strings(themes)
+		return themes, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// colorEnabled decides whether to colorize from --color, NO_COLOR and whether output goes to a terminal
+func colorEnabled() bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if _, set := os.LookupEnv("NO_COLOR"); set || copyOutput || outputFile != "" {
+		// Escape codes would end up in the clipboard or the --output-file too
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether the file is attached to a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// activeColorScheme returns the --theme scheme with any MYCLI_COLORS overrides applied
+func activeColorScheme() (colorScheme, error) {
+	scheme, ok := colorThemes[colorTheme]
+	if !ok {
+		return scheme, fmt.Errorf("unknown color theme: %s", colorTheme)
+	}
+
+	overrides := os.Getenv("MYCLI_COLORS")
+	if overrides == "" {
+		return scheme, nil
+	}
+	for _, entry := range strings.Split(overrides, ":") {
+		name, value, _ := strings.Cut(entry, "=")
+		switch name {
+		case "key":
+			scheme.Key = value
+		case "string":
+			scheme.String = value
+		case "number":
+			scheme.Number = value
+		case "bool":
+			scheme.Bool = value
+		case "null":
+			scheme.Null = value
+		default:
+			return scheme, fmt.Errorf("unknown MYCLI_COLORS token type: %s", name)
+		}
+	}
+	return scheme, nil
+}
+
+// marshalJSON encodes data as indented or compact JSON, colorized when color is enabled
+func marshalJSON(data interface{}, compact bool) ([]byte, error) {
+	if !colorEnabled() {
+		if compact {
+			return json.Marshal(data)
+		}
+		indent, err := indentString()
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(data, "", indent)
+	}
+
+	scheme, err := activeColorScheme()
+	if err != nil {
+		return nil, err
+	}
+	indent, err := indentString()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeColorJSON(&buf, scheme, indent, data, compact, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeColorJSON writes data as JSON with ANSI colors, matching the layout of encoding/json
+func writeColorJSON(buf *bytes.Buffer, scheme colorScheme, indent string, data interface{}, compact bool, depth int) error {
+	newline := func(depth int) {
+		if !compact {
+			buf.WriteString("\n" + strings.Repeat(indent, depth))
+		}
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			newline(depth + 1)
+			encoded, _ := json.Marshal(key)
+			writeColored(buf, scheme.Key, string(encoded))
+			buf.WriteString(":")
+			if !compact {
+				buf.WriteString(" ")
+			}
+			if err := writeColorJSON(buf, scheme, indent, v[key], compact, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(depth)
+		buf.WriteString("}")
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			newline(depth + 1)
+			if err := writeColorJSON(buf, scheme, indent, item, compact, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(depth)
+		buf.WriteString("]")
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		color := scheme.Number
+		switch v.(type) {
+		case nil:
+			color = scheme.Null
+		case bool:
+			color = scheme.Bool
+		case string:
+			color = scheme.String
+		}
+		writeColored(buf, color, string(encoded))
+	}
+	return nil
+}
+
+// writeColored wraps text in an ANSI SGR sequence, or writes it plain when no color is set
+func writeColored(buf *bytes.Buffer, sgr string, text string) {
+	if sgr == "" {
+		buf.WriteString(text)
+		return
+	}
+	buf.WriteString("\x1b[" + sgr + "m" + text + "\x1b[0m")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cliConfig is the persistent configuration stored in the config file
+type cliConfig struct {
+	Queries map[string]string `yaml:"queries,omitempty"`
+}
+
+// configPath returns MYCLI_CONFIG, or config.yaml in the user's mycli config directory
+func configPath() (string, error) {
+	if path := os.Getenv("MYCLI_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Error locating config directory: %v", err)
+	}
+	return filepath.Join(dir, "mycli", "config.yaml"), nil
+}
+
+// loadConfig reads the config file, treating a missing file as an empty configuration
+func loadConfig() (*cliConfig, error) {
+	config := &cliConfig{}
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading config file: %v", err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("Error parsing config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// saveConfig writes the config file atomically, creating its directory if needed
+func saveConfig(config *cliConfig) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("Error creating config directory: %v", err)
+	}
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("Error writing config file: %v", err)
+	}
+	return nil
+}
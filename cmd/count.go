@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// countCmd represents the count command
+var countCmd = &cobra.Command{
+	Use:   "count [jsonpath]",
+	Short: "Print how many values a path matches",
+	Long: `Print how many values a path matches. A path that selects a single array, object or
+string prints its length instead, and one that selects null or another scalar prints 1.
+Exits with status 1 when the count is zero:
+
+  $ mycli count -f pods.json '$.items[?(@.status.phase == "Failed")]' || echo "all healthy"`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+
+		// A path that does not exist counts as no matches rather than an error
+		value := jsonData
+		if len(args) == 1 {
+			query, err := compileQuery(queryLanguage, args[0], []interface{}{}, true)
+			if err != nil {
+				return err
+			}
+			if value, err = query(jsonData); err != nil {
+				return err
+			}
+		}
+
+		// A null that was matched is one match, not an empty value
+		count := 1
+		if value != nil {
+			count = valueLength(value)
+		}
+		fmt.Fprintln(output, count)
+		if count == 0 {
+			return errFalseResult
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(countCmd)
+
+	countCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	countCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+}
+
+// valueLength returns the number of elements, members or characters in a value; null
+// has none and other scalars count as one
+func valueLength(value interface{}) int {
+	switch v := value.(type) {
+	case []interface{}:
+		return len(v)
+	case map[string]interface{}:
+		return len(v)
+	case string:
+		return utf8.RuneCountInString(v)
+	case nil:
+		return 0
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaPath string
+
+// defaultsCmd represents the defaults command
+var defaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Fill in missing members from a JSON Schema's defaults",
+	Long: `Add every member a JSON Schema gives a default for but the document lacks, printing the
+resolved document or rewriting the file with -i. Defaults are found through properties,
+additionalProperties, items, prefixItems, allOf and local $ref references; anyOf and oneOf
+are ambiguous and not followed. A missing object is created when members inside it have
+defaults:
+
+  $ mycli defaults -f config.json --schema config.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := loadFile(schemaPath)
+		if err != nil {
+			return fmt.Errorf("%s: %v", schemaPath, err)
+		}
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+
+		filler := &defaultsFiller{root: schema, creating: map[string]bool{}}
+		if jsonData, err = filler.fill(jsonData, schema); err != nil {
+			return fmt.Errorf("%s: %v", schemaPath, err)
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(defaultsCmd)
+
+	addEditFlags(defaultsCmd)
+	defaultsCmd.Flags().StringVar(&schemaPath, "schema", "", "JSON Schema file whose defaults to apply")
+	defaultsCmd.MarkFlagRequired("schema")
+	defaultsCmd.MarkFlagFilename("schema", "json", "yaml", "yml")
+}
+
+// defaultsFiller applies the defaults of a schema. creating holds the references being
+// followed to create missing objects, so a recursive schema does not create them forever.
+type defaultsFiller struct {
+	root     interface{}
+	creating map[string]bool
+}
+
+// fill adds the defaults of schema to value, which it may modify, and returns the result
+func (f *defaultsFiller) fill(value interface{}, schema interface{}) (interface{}, error) {
+	s, _, err := f.resolve(schema)
+	if s == nil || err != nil {
+		return value, err
+	}
+
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, subschema := range all {
+			if value, err = f.fill(value, subschema); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := s["properties"].(map[string]interface{})
+		for _, key := range sortedKeys(properties) {
+			if child, ok := v[key]; ok {
+				if v[key], err = f.fill(child, properties[key]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			created, ok, err := f.missing(properties[key])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				v[key] = created
+			}
+		}
+
+		if additional, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			for _, key := range sortedKeys(v) {
+				if _, declared := properties[key]; declared {
+					continue
+				}
+				if v[key], err = f.fill(v[key], additional); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case []interface{}:
+		// prefixItems, or items as an array before draft 2020-12, describe leading elements
+		prefix, ok := s["prefixItems"].([]interface{})
+		if !ok {
+			prefix, _ = s["items"].([]interface{})
+		}
+		rest, _ := s["items"].(map[string]interface{})
+		for i := range v {
+			var itemSchema interface{} = rest
+			if i < len(prefix) {
+				itemSchema = prefix[i]
+			}
+			if itemSchema == nil {
+				continue
+			}
+			if v[i], err = f.fill(v[i], itemSchema); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
+
+// missing returns the value a missing member gets from its schema: a copy of its default, or
+// an object holding the defaults of its own members. ok is false when there is neither.
+func (f *defaultsFiller) missing(schema interface{}) (value interface{}, ok bool, err error) {
+	s, ref, err := f.resolve(schema)
+	if s == nil || err != nil {
+		return nil, false, err
+	}
+	if value, ok := s["default"]; ok {
+		value, err := f.fill(copyValue(value), schema)
+		return value, err == nil, err
+	}
+
+	if _, ok := s["properties"]; !ok || f.creating[ref] {
+		return nil, false, nil
+	}
+	if ref != "" {
+		f.creating[ref] = true
+		defer delete(f.creating, ref)
+	}
+	created, err := f.fill(map[string]interface{}{}, schema)
+	if err != nil {
+		return nil, false, err
+	}
+	return created, len(created.(map[string]interface{})) > 0, nil
+}
+
+// resolve follows $ref references within the schema document, returning the schema object
+// they lead to, or nil for a boolean schema, along with the last reference followed
+func (f *defaultsFiller) resolve(schema interface{}) (map[string]interface{}, string, error) {
+	ref := ""
+	for hops := 0; ; hops++ {
+		s, ok := schema.(map[string]interface{})
+		if !ok {
+			return nil, ref, nil
+		}
+		next, ok := s["$ref"].(string)
+		if !ok {
+			return s, ref, nil
+		}
+		if hops == 32 {
+			return nil, ref, fmt.Errorf("$ref %s: too many nested references", next)
+		}
+		if !strings.HasPrefix(next, "#") {
+			return nil, ref, fmt.Errorf("$ref %s: only references within the schema (#/...) are supported", next)
+		}
+		pointer, err := url.PathUnescape(next[1:])
+		if err != nil {
+			return nil, ref, fmt.Errorf("$ref %s: %v", next, err)
+		}
+		if schema, err = resolvePointer(f.root, pointer); err != nil {
+			return nil, ref, fmt.Errorf("$ref %s: %v", next, err)
+		}
+		ref = next
+	}
+}
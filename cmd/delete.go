@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var deleteLimit int
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete <jsonpath>",
+	Short: "Delete the members or elements a path matches",
+	Long: `Delete the object members or array elements a path matches and print the modified
+document, or rewrite the file with -i. A path that matches nothing leaves the document as
+it is. As a safeguard, a path matching more than --limit values is refused:
+
+  $ mycli delete -f config.json '$.debug' -i
+  $ mycli delete -f pods.json '$.items[?(@.status.phase == "Succeeded")]' --limit 0`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPath := args[0]
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+
+		locations, err := locateJSONPath(jsonData, jsonPath)
+		if err != nil {
+			return jsonPathError(jsonPath, err)
+		}
+		if deleteLimit > 0 && len(locations) > deleteLimit {
+			return fmt.Errorf("%s matches %d values, more than --limit %d; raise --limit to delete them all", jsonPath, len(locations), deleteLimit)
+		}
+		for _, loc := range locations {
+			if len(loc) == 0 {
+				return fmt.Errorf("Cannot delete the document root")
+			}
+		}
+
+		return saveEditedDocument(removeLocations(jsonData, locations))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	addEditFlags(deleteCmd)
+	deleteCmd.Flags().IntVar(&deleteLimit, "limit", 1, "Refuse to delete more than this many matches (0 for no limit)")
+}
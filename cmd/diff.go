@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/spf13/cobra"
+)
+
+// difference is one change between two documents. path locates it for display, in the
+// first document for removals and changes and in the second for additions; patchPath is
+// where a JSON Patch applying the changes in order finds it.
+type difference struct {
+	op        string
+	path      location
+	patchPath location
+	old, new  interface{}
+}
+
+// diffOptions control which differences between documents are significant
+type diffOptions struct {
+	// ignoreOrder compares arrays as multisets
+	ignoreOrder bool
+	// tolerance is how far apart two numbers may be and still count as equal
+	tolerance float64
+}
+
+var (
+	diffIgnoreOrder bool
+	diffIgnorePaths []string
+	diffTolerance   float64
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Compare two documents structurally",
+	Long: `Compare two documents structurally, listing each removed (-), added (+) and changed (~)
+path with --context unchanged neighbours. With -o patch, print a minimal JSON Patch
+(RFC 6902) that turns the first document into the second instead; other -o formats render
+that patch. Arrays are aligned on their longest common subsequence so an insertion is not
+reported as a change to every later element, or matched regardless of position with
+--ignore-array-order. Values matched by --ignore-path are left out of the comparison, and
+numbers within --tolerance of each other count as equal. Exits with status 1 when the
+documents differ:
+
+  $ mycli diff before.json after.json
+  $ mycli diff expected.json actual.json --ignore-path '$.metadata.timestamp' --tolerance 1e-9
+  $ mycli diff before.json after.json -o patch > changes.json
+  $ mycli patch -f before.json changes.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := loadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("%s: %v", args[0], err)
+		}
+		b, err := loadFile(args[1])
+		if err != nil {
+			return fmt.Errorf("%s: %v", args[1], err)
+		}
+		if diffTolerance < 0 {
+			return fmt.Errorf("--tolerance cannot be negative")
+		}
+		if a, err = dropIgnoredPaths(a, diffIgnorePaths); err != nil {
+			return err
+		}
+		if b, err = dropIgnoredPaths(b, diffIgnorePaths); err != nil {
+			return err
+		}
+		options := diffOptions{ignoreOrder: diffIgnoreOrder, tolerance: diffTolerance}
+
+		// The patch formats are chosen with -o; otherwise changes are listed for reading
+		if cmd.Flags().Changed("output") {
+			differences := diffValues(a, b, options, false)
+			if err := printPatch(differences); err != nil {
+				return err
+			}
+			if len(differences) > 0 {
+				return errFalseResult
+			}
+			return nil
+		}
+
+		differences := diffValues(a, b, options, true)
+		if differences[0].op == "same" {
+			return nil
+		}
+		if _, err := output.Write(formatDiff(args[0], args[1], differences)); err != nil {
+			return err
+		}
+		return errFalseResult
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	addResultFlags(diffCmd)
+
+	diffCmd.Flags().BoolVar(&diffIgnoreOrder, "ignore-array-order", false, "Match array elements regardless of their position")
+	diffCmd.Flags().StringArrayVar(&diffIgnorePaths, "ignore-path", nil, "Leave values matched by this JSONPath out of the comparison (repeatable)")
+	diffCmd.Flags().Float64Var(&diffTolerance, "tolerance", 0, "Treat numbers differing by at most this much as equal")
+}
+
+// dropIgnoredPaths removes every value the --ignore-path expressions match
+func dropIgnoredPaths(jsonData interface{}, jsonPaths []string) (interface{}, error) {
+	for _, jsonPath := range jsonPaths {
+		locations, err := locateJSONPath(jsonData, jsonPath)
+		if err != nil {
+			return nil, jsonPathError(jsonPath, err)
+		}
+		for _, loc := range locations {
+			if len(loc) == 0 {
+				return nil, fmt.Errorf("Cannot ignore the document root")
+			}
+		}
+		jsonData = removeLocations(jsonData, locations)
+	}
+	return jsonData, nil
+}
+
+// differ compares documents. With withContext it also records, for display, unchanged
+// members and elements ("same") and containers whose contents changed ("nested").
+type differ struct {
+	diffOptions
+	withContext bool
+	differences []difference
+}
+
+// diffValues returns the changes that turn a into b
+func diffValues(a, b interface{}, options diffOptions, withContext bool) []difference {
+	d := &differ{diffOptions: options, withContext: withContext, differences: []difference{}}
+	d.compare(a, b, location{}, location{})
+	return d.differences
+}
+
+// record appends a change, or an unchanged entry when context is wanted
+func (d *differ) record(entry difference) {
+	if d.withContext || (entry.op != "same" && entry.op != "nested") {
+		d.differences = append(d.differences, entry)
+	}
+}
+
+// compare compares two values found at path, recording the changes between them
+func (d *differ) compare(a, b interface{}, path location, patchPath location) {
+	if d.equal(a, b) {
+		d.record(difference{op: "same", path: path, patchPath: patchPath, old: a, new: b})
+		return
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			d.record(difference{op: "nested", path: path, patchPath: patchPath})
+			d.compareObjects(x, y, path, patchPath)
+			return
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			d.record(difference{op: "nested", path: path, patchPath: patchPath})
+			if d.ignoreOrder {
+				d.compareUnordered(x, y, path, patchPath)
+			} else {
+				d.compareArrays(x, y, path, patchPath)
+			}
+			return
+		}
+	}
+	d.record(difference{op: "replace", path: path, patchPath: patchPath, old: a, new: b})
+}
+
+// compareObjects reports removed members, then common ones, then added ones, each in key order
+func (d *differ) compareObjects(a, b map[string]interface{}, path location, patchPath location) {
+	for _, key := range sortedKeys(a) {
+		if _, ok := b[key]; !ok {
+			d.record(difference{op: "remove", path: appendLocation(path, key), patchPath: appendLocation(patchPath, key), old: a[key]})
+		}
+	}
+	for _, key := range sortedKeys(a) {
+		if value, ok := b[key]; ok {
+			d.compare(a[key], value, appendLocation(path, key), appendLocation(patchPath, key))
+		}
+	}
+	for _, key := range sortedKeys(b) {
+		if _, ok := a[key]; !ok {
+			d.record(difference{op: "add", path: appendLocation(path, key), patchPath: appendLocation(patchPath, key), new: b[key]})
+		}
+	}
+}
+
+// compareArrays aligns two arrays on their longest common subsequence. Within each gap between
+// common elements, removed and added elements are paired up and compared, and the rest are
+// removed or added. position tracks the index in the array as patched so far.
+func (d *differ) compareArrays(a, b []interface{}, path location, patchPath location) {
+	common := longestCommonSubsequence(a, b, d.equal)
+
+	i, j, position := 0, 0, 0
+	for _, match := range append(common, [2]int{len(a), len(b)}) {
+		for i < match[0] && j < match[1] {
+			d.compare(a[i], b[j], appendLocation(path, i), appendLocation(patchPath, position))
+			i, j, position = i+1, j+1, position+1
+		}
+		for ; i < match[0]; i++ {
+			d.record(difference{op: "remove", path: appendLocation(path, i), patchPath: appendLocation(patchPath, position), old: a[i]})
+		}
+		for ; j < match[1]; j++ {
+			d.record(difference{op: "add", path: appendLocation(path, j), patchPath: appendLocation(patchPath, position), new: b[j]})
+			position++
+		}
+		if i < len(a) {
+			d.record(difference{op: "same", path: appendLocation(path, j), patchPath: appendLocation(patchPath, position), old: a[i], new: b[j]})
+		}
+		// Step over the common element itself
+		i, j, position = i+1, j+1, position+1
+	}
+}
+
+// compareUnordered matches each element of a with an equal one anywhere in b. Unmatched
+// elements are paired up in order and compared, and the rest are removed from a or added
+// at the end.
+func (d *differ) compareUnordered(a, b []interface{}, path location, patchPath location) {
+	matched := map[int]bool{}
+	used := map[int]bool{}
+	for _, pair := range d.matchElements(a, b) {
+		matched[pair[0]] = true
+		used[pair[1]] = true
+	}
+	unmatched := []int{}
+	for j := range b {
+		if !used[j] {
+			unmatched = append(unmatched, j)
+		}
+	}
+
+	position := 0
+	for i := range a {
+		switch {
+		case matched[i]:
+			d.record(difference{op: "same", path: appendLocation(path, i), patchPath: appendLocation(patchPath, position), old: a[i], new: a[i]})
+			position++
+		case len(unmatched) > 0:
+			d.compare(a[i], b[unmatched[0]], appendLocation(path, i), appendLocation(patchPath, position))
+			unmatched = unmatched[1:]
+			position++
+		default:
+			d.record(difference{op: "remove", path: appendLocation(path, i), patchPath: appendLocation(patchPath, position), old: a[i]})
+		}
+	}
+	for _, j := range unmatched {
+		d.record(difference{op: "add", path: appendLocation(path, j), patchPath: appendLocation(patchPath, position), new: b[j]})
+		position++
+	}
+}
+
+// matchElements pairs each element of a with the first unused equal element of b
+func (options diffOptions) matchElements(a, b []interface{}) [][2]int {
+	pairs := [][2]int{}
+	used := make([]bool, len(b))
+	for i := range a {
+		for j := range b {
+			if !used[j] && options.equal(a[i], b[j]) {
+				pairs = append(pairs, [2]int{i, j})
+				used[j] = true
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+// equal reports whether two values are equal once array order and small numeric
+// differences are disregarded as the options ask
+func (options diffOptions) equal(a, b interface{}) bool {
+	if !options.ignoreOrder && options.tolerance == 0 {
+		return equalValues(a, b)
+	}
+
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		return ok && math.Abs(x-y) <= options.tolerance
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			if other, ok := y[key]; !ok || !options.equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		if options.ignoreOrder {
+			return len(options.matchElements(x, y)) == len(x)
+		}
+		for i := range x {
+			if !options.equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return equalValues(a, b)
+}
+
+// longestCommonSubsequence returns the index pairs of equal elements in a longest common
+// subsequence of two arrays
+func longestCommonSubsequence(a, b []interface{}, equal func(a, b interface{}) bool) [][2]int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if equal(a[i], b[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	pairs := [][2]int{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case equal(a[i], b[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// equalValues reports whether two decoded values are deeply equal
+func equalValues(a, b interface{}) bool {
+	return compareValues(a, b) == 0
+}
+
+// patchOperations renders differences as the operations of a JSON Patch
+func patchOperations(differences []difference) []interface{} {
+	operations := make([]interface{}, len(differences))
+	for i, d := range differences {
+		operation := map[string]interface{}{"op": d.op, "path": d.patchPath.pointer()}
+		if d.op != "remove" {
+			operation["value"] = d.new
+		}
+		operations[i] = operation
+	}
+	return operations
+}
+
+// printPatch prints differences as a JSON Patch, in JSON for -o patch and otherwise in
+// the selected output format
+func printPatch(differences []difference) error {
+	operations := patchOperations(differences)
+	if outputFormat != "patch" {
+		return prettyPrintJSON(operations)
+	}
+
+	data, err := formatJSON(operations)
+	if err != nil {
+		return fmt.Errorf("Error formatting output: %v", err)
+	}
+	_, err = io.WriteString(output, string(data)+"\n")
+	return err
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+var diffContext int
+
+// diffLineColors are the ANSI SGR parameters for each kind of line in a text diff
+var diffLineColors = map[string]string{
+	"remove":  "31",
+	"add":     "32",
+	"replace": "33",
+	"same":    "2",
+	"header":  "1",
+}
+
+func init() {
+	diffCmd.Flags().IntVarP(&diffContext, "context", "U", 2, "Unchanged members and elements to show around each change")
+}
+
+// formatDiff renders differences recorded with context as one line per changed path:
+// - for removals, + for additions and ~ for changes, with up to --context unchanged
+// siblings around them and … where unchanged ones are left out
+func formatDiff(nameA, nameB string, differences []difference) []byte {
+	// Group each entry under the container it belongs to, in the order they were found
+	children := map[string][]difference{}
+	for _, d := range differences {
+		if len(d.path) > 0 {
+			parent := d.path[:len(d.path)-1].String()
+			children[parent] = append(children[parent], d)
+		}
+	}
+
+	color := colorEnabled()
+	var buf bytes.Buffer
+	line := func(kind string, text string) {
+		if color {
+			writeColored(&buf, diffLineColors[kind], text)
+		} else {
+			buf.WriteString(text)
+		}
+		buf.WriteString("\n")
+	}
+
+	line("header", "--- "+nameA)
+	line("header", "+++ "+nameB)
+
+	var render func(entry difference)
+	render = func(entry difference) {
+		switch entry.op {
+		case "remove":
+			line("remove", "- "+entry.path.String()+": "+diffValueText(entry.old))
+		case "add":
+			line("add", "+ "+entry.path.String()+": "+diffValueText(entry.new))
+		case "replace":
+			line("replace", "~ "+entry.path.String()+": "+diffValueText(entry.old)+" → "+diffValueText(entry.new))
+		case "same":
+			line("same", "  "+entry.path.String()+": "+diffValueText(entry.new))
+		case "nested":
+			entries := children[entry.path.String()]
+			shown := contextEntries(entries, diffContext)
+			skipped := false
+			for i, child := range entries {
+				if !shown[i] {
+					skipped = true
+					continue
+				}
+				if skipped {
+					line("same", "  "+ellipsis)
+				}
+				skipped = false
+				render(child)
+			}
+		}
+	}
+	render(differences[0])
+	return buf.Bytes()
+}
+
+// contextEntries marks which entries of a container to show: every change, and unchanged
+// entries within context positions of one
+func contextEntries(entries []difference, context int) []bool {
+	shown := make([]bool, len(entries))
+	for i, entry := range entries {
+		if entry.op == "same" {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(entries)-1, i+context); j++ {
+			shown[j] = true
+		}
+	}
+	return shown
+}
+
+// diffValueText renders a value on one line as compact JSON, truncated to --max-string
+func diffValueText(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return truncateText(string(encoded), truncationLimit(maxStringLength))
+}
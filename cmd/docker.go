@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var dockerContainer string
+
+func init() {
+	readCmd.Flags().StringVar(&dockerContainer, "docker", "", "Docker container (or image) whose inspect output is queried")
+
+	// Complete container names from the local Docker daemon
+	readCmd.RegisterFlagCompletionFunc("docker", dockerContainerCompletion)
+}
+
+// loadDockerDocument runs docker inspect on a container and parses the result
+func loadDockerDocument(container string) (interface{}, error) {
+	output, err := runCommand("docker", "inspect", container)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(parseJSON, output)
+}
+
+// dockerContainerCompletion suggests the names of all containers, running or stopped
+func dockerContainerCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := runLinesCommand("docker", "ps", "--all", "--format", "{{.Names}}")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// dotLabelLimit caps the length of scalar values shown in DOT node labels
+const dotLabelLimit = 40
+
+// formatDOT renders the structure of a result as a Graphviz digraph
+func formatDOT(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("digraph json {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [shape=box, fontname=\"monospace\"];\n")
+
+	next := 0
+	var walk func(key string, value interface{}) string
+	walk = func(key string, value interface{}) string {
+		id := "n" + strconv.Itoa(next)
+		next++
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			writeDOTNode(&buf, id, fmt.Sprintf("%s {%d}", key, len(v)), "ellipse")
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(&buf, "  %s -> %s;\n", id, walk(k, v[k]))
+			}
+		case []interface{}:
+			writeDOTNode(&buf, id, fmt.Sprintf("%s [%d]", key, len(v)), "ellipse")
+			for i, item := range v {
+				fmt.Fprintf(&buf, "  %s -> %s;\n", id, walk("["+strconv.Itoa(i)+"]", item))
+			}
+		default:
+			encoded, _ := json.Marshal(v)
+			writeDOTNode(&buf, id, key+": "+truncateText(string(encoded), dotLabelLimit), "box")
+		}
+		return id
+	}
+	walk("$", data)
+
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// writeDOTNode declares a node with an escaped label
+func writeDOTNode(buf *bytes.Buffer, id string, label string, shape string) {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(label)
+	fmt.Fprintf(buf, "  %s [label=\"%s\", shape=%s];\n", id, escaped, shape)
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseDotenv decodes a dotenv file into a flat object of variable names to string values
+func parseDotenv(data []byte) (interface{}, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	result := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("Error parsing dotenv: line %d: missing '='", i+1)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			// Double-quoted values may span lines and support escapes
+			for !closesDoubleQuote(value) && i+1 < len(lines) {
+				i++
+				value += "\n" + lines[i]
+			}
+			end := strings.LastIndex(value, `"`)
+			if end == 0 {
+				return nil, fmt.Errorf("Error parsing dotenv: unterminated quote for %s", key)
+			}
+			unquoted, err := strconv.Unquote(value[:end+1])
+			if err != nil {
+				// strconv rejects literal newlines, so fall back to minimal unescaping
+				unquoted = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1:end])
+			}
+			value = unquoted
+		case strings.HasPrefix(value, "'"):
+			// Single-quoted values are taken literally
+			end := strings.LastIndex(value, "'")
+			if end == 0 {
+				return nil, fmt.Errorf("Error parsing dotenv: unterminated quote for %s", key)
+			}
+			value = value[1:end]
+		default:
+			// Strip trailing comments from unquoted values
+			if idx := strings.Index(value, " #"); idx != -1 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// closesDoubleQuote reports whether a value starting with '"' contains its closing quote
+func closesDoubleQuote(value string) bool {
+	escaped := false
+	for _, r := range value[1:] {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return true
+		}
+	}
+	return false
+}
+
+// parseProperties decodes a Java .properties file into a flat object of keys to string values
+func parseProperties(data []byte) (interface{}, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	result := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// A trailing odd run of backslashes continues the entry on the next line
+		for endsWithContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		key, value := splitProperty(line)
+		result[unescapeProperty(key)] = unescapeProperty(value)
+	}
+
+	return result, nil
+}
+
+// endsWithContinuation reports whether a line ends in an unescaped backslash
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// splitProperty splits a logical line at the first unescaped '=', ':' or whitespace
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return line[:i], rest
+		}
+	}
+	return line, ""
+}
+
+// unescapeProperty resolves the escape sequences allowed in .properties keys and values
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if code, err := strconv.ParseUint(s[i+1:i+5], 16, 16); err == nil {
+					b.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultEditor is used when neither VISUAL nor EDITOR is set
+const defaultEditor = "vi"
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit [jsonpath]",
+	Short: "Edit the value at a path in $EDITOR",
+	Long: `Open the value a path matches, or the whole document, as JSON in $VISUAL or $EDITOR.
+When the editor exits the result is checked, offering to reopen it if it is not valid JSON,
+and spliced back into the document, which is printed or, with -i, written back to the file:
+
+  $ mycli edit -f deployment.json '$.spec.template' -i`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPath := "$"
+		if len(args) > 0 {
+			jsonPath = args[0]
+		}
+		if filePath == "" {
+			// stdin stays with the terminal for the editor
+			return errNoSource
+		}
+
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		locations, err := locateJSONPath(jsonData, jsonPath)
+		if err != nil {
+			return jsonPathError(jsonPath, err)
+		}
+		if len(locations) != 1 {
+			return fmt.Errorf("%s must match exactly one value, matched %d", jsonPath, len(locations))
+		}
+
+		value, _ := getAt(jsonData, locations[0])
+		edited, changed, err := editValue(value)
+		if err != nil {
+			return err
+		}
+		if !changed && inPlace {
+			return nil
+		}
+		return saveEditedDocument(setAt(jsonData, locations[0], edited))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	addEditFlags(editCmd)
+}
+
+// editValue lets the user edit a value as indented JSON in their editor, reopening it for as
+// long as they want to fix what does not parse. changed is false when the text was saved as is.
+func editValue(value interface{}) (interface{}, bool, error) {
+	indent, err := indentString()
+	if err != nil {
+		return nil, false, err
+	}
+	original, err := json.MarshalIndent(value, "", indent)
+	if err != nil {
+		return nil, false, fmt.Errorf("Error formatting value: %v", err)
+	}
+
+	file, err := os.CreateTemp("", "mycli-edit-*.json")
+	if err != nil {
+		return nil, false, fmt.Errorf("Error creating temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(append(original, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("Error writing temporary file: %v", err)
+	}
+
+	for {
+		if err := runEditor(file.Name()); err != nil {
+			return nil, false, err
+		}
+		data, err := os.ReadFile(file.Name())
+		if err != nil {
+			return nil, false, fmt.Errorf("Error reading temporary file: %v", err)
+		}
+		if bytes.Equal(bytes.TrimSpace(data), original) {
+			return value, false, nil
+		}
+
+		var edited interface{}
+		err = json.Unmarshal(data, &edited)
+		if err == nil {
+			return edited, true, nil
+		}
+		fmt.Fprintf(os.Stderr, "Error parsing JSON: %v\nReopen the editor? [Y/n] ", err)
+		if !readYes() {
+			return nil, false, fmt.Errorf("Edit abandoned; the document is unchanged")
+		}
+	}
+}
+
+// readYes reads an answer line from stdin, where an empty answer means yes. It reads a byte
+// at a time so nothing typed after the answer is taken from the editor, and treats a closed
+// or unreadable stdin as no.
+func readYes() bool {
+	var answer []byte
+	buf := make([]byte, 1)
+	for {
+		if _, err := os.Stdin.Read(buf); err != nil {
+			return false
+		}
+		if buf[0] == '\n' {
+			break
+		}
+		answer = append(answer, buf[0])
+	}
+	switch strings.ToLower(strings.TrimSpace(string(answer))) {
+	case "", "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// runEditor opens a file in $VISUAL or $EDITOR, through the shell so the setting may carry
+// its own arguments, such as code --wait
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	command := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	// The editor draws on stderr so it still reaches the terminal when the result is redirected
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stderr
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("Error running editor %s: %v; the document is unchanged", editor, err)
+	}
+	return nil
+}
+
+// addEditFlags registers the input, --in-place and result flags shared by the commands that
+// modify a document
+func addEditFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&filePath, "file", "f", "", "File to modify (defaults to JSON on stdin)")
+	cmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	addInPlaceFlags(cmd)
+	addResultFlags(cmd)
+}
+
+// loadEditDocument loads the document a modifying command works on
+func loadEditDocument() (interface{}, error) {
+	if err := validateInPlace(filePath); err != nil {
+		return nil, err
+	}
+	if inPlace {
+		// Refuse before doing any work rather than after
+		if _, err := inPlaceEncoder(filePath); err != nil {
+			return nil, err
+		}
+	}
+	return loadDocumentOrStdin()
+}
+
+// saveEditedDocument prints a modified document, or with --in-place writes it back to the file
+// it was read from in that file's format
+func saveEditedDocument(jsonData interface{}) error {
+	if !inPlace {
+		return prettyPrintJSON(jsonData)
+	}
+	return writeDocument(filePath, jsonData)
+}
+
+// inPlaceEncoders write a document back to a file in the file's format. Unlike the output
+// formatters they ignore --color, -o and --compact, so the file never gets escape codes.
+// Formats whose encoding would change members the edit did not touch, such as env with its
+// uppercased, flattened names, are left out so they cannot be rewritten.
+var inPlaceEncoders = map[string]outputFormatter{
+	"json": formatPlainJSON,
+	"xlsx": formatXLSX,
+}
+
+// inPlaceEncoder returns the encoder for writing a file back in its own format
+func inPlaceEncoder(path string) (outputFormatter, error) {
+	format := formatForPath(path)
+	if format == "" {
+		format = "json"
+	}
+	encode, ok := inPlaceEncoders[format]
+	if !ok {
+		return nil, fmt.Errorf("cannot write %s files in place; print the result without -i instead", format)
+	}
+	return encode, nil
+}
+
+// writeDocument replaces a file with a document encoded in the file's format
+func writeDocument(path string, jsonData interface{}) error {
+	format := formatForPath(path)
+	if format == "" {
+		format = "json"
+	}
+	encode, err := inPlaceEncoder(path)
+	if err != nil {
+		return err
+	}
+	data, err := encode(jsonData)
+	if err != nil {
+		return fmt.Errorf("Error formatting output: %v", err)
+	}
+	if !binaryOutputFormats[format] {
+		data = append(data, '\n')
+	}
+	return writeInPlace(path, data)
+}
+
+// formatPlainJSON renders data as indented JSON without color, whatever the output flags say
+func formatPlainJSON(data interface{}) ([]byte, error) {
+	indent, err := indentString()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(data, "", indent)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"unicode/utf16"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// normalizeEncoding detects a byte order mark or UTF-16 encoding and returns the data as UTF-8
+func normalizeEncoding(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return data[len(bomUTF8):], nil
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return decodeUTF16(data[len(bomUTF16LE):], false)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return decodeUTF16(data[len(bomUTF16BE):], true)
+	}
+
+	// No BOM: JSON text starts with an ASCII character, so a zero byte in
+	// one of the first two positions indicates UTF-16 without a BOM
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0 && data[1] != 0:
+			return decodeUTF16(data, true)
+		case data[0] != 0 && data[1] == 0:
+			return decodeUTF16(data, false)
+		}
+	}
+
+	return data, nil
+}
+
+// decodeUTF16 transcodes UTF-16 data in the given byte order to UTF-8
+func decodeUTF16(data []byte, bigEndian bool) ([]byte, error) {
+	if len(data)%2 != 0 {
+		return nil, errors.New("invalid UTF-16 input: odd number of bytes")
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, r := range utf16.Decode(units) {
+		buf.WriteRune(r)
+	}
+	return buf.Bytes(), nil
+}
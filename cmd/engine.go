@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	rfc9535 "github.com/speakeasy-api/jsonpath/pkg/jsonpath"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var jsonPathEngineName string
+
+// errNoMatch is reported by engines when a path that selects a single value selects nothing
+var errNoMatch = errors.New("path matched nothing")
+
+// jsonPathEngine evaluates a JSONPath expression against a decoded document
+type jsonPathEngine func(jsonPath string, jsonData interface{}) (interface{}, error)
+
+// jsonPathEngines maps each supported --engine value to its implementation. It is filled
+// in init because engines evaluate filters through queryJSONPath, which looks them up here.
+var jsonPathEngines map[string]jsonPathEngine
+
+// paesslerLanguage extends JSONPath with gval's operators so filters can compare,
+// combine conditions with && and || and do arithmetic
+var paesslerLanguage = gval.NewLanguage(gval.Full(), jsonpath.Language())
+
+// gvalParseError matches the position and message of a gval parsing error
+var gvalParseError = regexp.MustCompile(`^parsing error: .*\t(?::(\d+):(\d+))? - (\d+):(\d+) (.*)$`)
+
+func init() {
+	jsonPathEngines = map[string]jsonPathEngine{
+		"paessler": queryPaessler,
+		"rfc9535":  queryRFC9535,
+	}
+
+	rootCmd.PersistentFlags().StringVar(&jsonPathEngineName, "engine", "paessler", "JSONPath engine: paessler or rfc9535")
+	rootCmd.RegisterFlagCompletionFunc("engine", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		engines := make([]string, 0, len(jsonPathEngines))
+		for engine := range jsonPathEngines {
+			engines = append(engines, engine)
+		}
+		sort.Strings(engines)
+		return engines, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// selectedEngine returns the engine chosen with --engine
+func selectedEngine() (jsonPathEngine, error) {
+	engine, ok := jsonPathEngines[jsonPathEngineName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported JSONPath engine: %s", jsonPathEngineName)
+	}
+	return engine, nil
+}
+
+// queryPaessler evaluates a path with the PaesslerAG engine
+func queryPaessler(jsonPath string, jsonData interface{}) (interface{}, error) {
+	jsonPath, err := rewriteRegexLiterals(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// PaesslerAG has no negative indices, diverges on negative slice bounds and cannot
+	// parse single-quoted keys, so such paths are evaluated by the locator instead
+	if segments, err := parsePathSegments(jsonPath); err == nil && needsLocator(segments) {
+		return queryLocated(jsonData, jsonPath)
+	}
+
+	jsonPath = rewriteSingleQuotes(jsonPath)
+	result, err := paesslerLanguage.Evaluate(jsonPath, jsonData)
+	if err != nil {
+		// Report the underlying problem, such as "unknown key", without gval's wrapping
+		if msg, ok := strings.CutPrefix(err.Error(), "can not evaluate "+jsonPath+": "); ok {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// rewriteRegexLiterals turns /pattern/flags after =~ into the quoted pattern gval expects,
+// folding the i, m and s flags into the pattern
+func rewriteRegexLiterals(jsonPath string) (string, error) {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(jsonPath); i++ {
+		c := jsonPath[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(jsonPath) {
+				b.WriteByte(c)
+				i++
+				c = jsonPath[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(jsonPath[i:], "=~"):
+			start := i + 2
+			for start < len(jsonPath) && jsonPath[start] == ' ' {
+				start++
+			}
+			if start >= len(jsonPath) || jsonPath[start] != '/' {
+				break
+			}
+
+			// Read up to the closing slash; \/ stands for a literal slash
+			var pattern strings.Builder
+			end := start + 1
+			for ; end < len(jsonPath) && jsonPath[end] != '/'; end++ {
+				if jsonPath[end] == '\\' && end+1 < len(jsonPath) {
+					if jsonPath[end+1] != '/' {
+						pattern.WriteByte('\\')
+					}
+					end++
+				}
+				pattern.WriteByte(jsonPath[end])
+			}
+			if end >= len(jsonPath) {
+				return "", fmt.Errorf("unterminated regular expression at column %d", start+1)
+			}
+
+			flags := ""
+			for end+1 < len(jsonPath) && strings.IndexByte("ims", jsonPath[end+1]) >= 0 {
+				end++
+				flags += string(jsonPath[end])
+			}
+			regex := pattern.String()
+			if flags != "" {
+				regex = "(?" + flags + ")" + regex
+			}
+			if _, err := regexp.Compile(regex); err != nil {
+				return "", fmt.Errorf("invalid regular expression at column %d: %v", start+1, err)
+			}
+
+			b.WriteString("=~ " + strconv.Quote(regex))
+			i = end
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// rewriteSingleQuotes turns single-quoted string literals, which gval cannot parse, into
+// double-quoted ones
+func rewriteSingleQuotes(jsonPath string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(jsonPath); i++ {
+		c := jsonPath[i]
+		switch {
+		case quote == '"':
+			if c == '\\' && i+1 < len(jsonPath) {
+				b.WriteByte(c)
+				i++
+				c = jsonPath[i]
+			} else if c == '"' {
+				quote = 0
+			}
+		case quote == '\'':
+			switch {
+			case c == '\\' && i+1 < len(jsonPath) && jsonPath[i+1] == '\'':
+				i++
+				c = '\''
+			case c == '\\' && i+1 < len(jsonPath):
+				b.WriteByte(c)
+				i++
+				c = jsonPath[i]
+			case c == '"':
+				b.WriteByte('\\')
+			case c == '\'':
+				quote = 0
+				c = '"'
+			}
+		case c == '"':
+			quote = c
+		case c == '\'':
+			quote = c
+			c = '"'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// needsLocator reports whether any segment uses a slice, a negative index, a union
+// or a single-quoted key
+func needsLocator(segments []pathSegment) bool {
+	for _, segment := range segments {
+		if segment.kind == selectSlice || segment.singleQuoted || len(segment.keys) > 1 || len(segment.indices) > 1 {
+			return true
+		}
+		for _, index := range segment.indices {
+			if index < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queryLocated evaluates a path with the locator, shaping the result like the engines do
+func queryLocated(jsonData interface{}, jsonPath string) (interface{}, error) {
+	results := []interface{}{}
+	err := walkJSONPath(jsonData, jsonPath, func(loc location, value interface{}) error {
+		results = append(results, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMultiMatchPath(jsonPath) {
+		if len(results) == 0 {
+			return nil, errNoMatch
+		}
+		return results[0], nil
+	}
+	return results, nil
+}
+
+// validateJSONPath checks the syntax of a path with the selected engine without evaluating it
+func validateJSONPath(jsonPath string) error {
+	var err error
+	switch jsonPathEngineName {
+	case "paessler":
+		if jsonPath, err = rewriteRegexLiterals(jsonPath); err == nil {
+			_, err = paesslerLanguage.NewEvaluable(rewriteSingleQuotes(jsonPath))
+		}
+	case "rfc9535":
+		_, err = rfc9535.NewPath(jsonPath)
+	}
+	return err
+}
+
+// jsonPathError wraps a query error, pointing at the position of a syntax error in the path
+func jsonPathError(jsonPath string, err error) error {
+	match := gvalParseError.FindStringSubmatch(err.Error())
+	if match == nil {
+		return fmt.Errorf("Error querying JSONPath: %v", err)
+	}
+
+	// Some errors only carry the end of the offending token
+	line, column := match[1], match[2]
+	if line == "" {
+		line, column = match[3], match[4]
+	}
+	col, _ := strconv.Atoi(column)
+	if line != "1" || col < 1 || col > utf8.RuneCountInString(jsonPath)+1 {
+		return fmt.Errorf("Error querying JSONPath: %s", match[5])
+	}
+	return fmt.Errorf("Error querying JSONPath: %s at column %d\n  %s\n  %s^", match[5], col, jsonPath, strings.Repeat(" ", col-1))
+}
+
+// queryRFC9535 evaluates a path with standard RFC 9535 semantics. Paths that can select
+// several values return the node list as an array; singular paths return the value itself.
+func queryRFC9535(jsonPath string, jsonData interface{}) (interface{}, error) {
+	path, err := rfc9535.NewPath(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root := yamlNodeFromValue(jsonData)
+	nodes := path.Query(root)
+	if !isMultiMatchPath(jsonPath) {
+		if len(nodes) == 0 {
+			return nil, errNoMatch
+		}
+		return valueFromYAMLNode(nodes[0])
+	}
+
+	results := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		value, err := valueFromYAMLNode(node)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, value)
+	}
+	return results, nil
+}
+
+// yamlNodeFromValue builds the node tree the RFC 9535 engine queries, tagging scalars
+// explicitly so strings such as "true" keep their type
+func yamlNodeFromValue(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, yamlNodeFromValue(v[key]))
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNodeFromValue(item))
+		}
+		return node
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: strconv.FormatFloat(v, 'g', -1, 64)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// valueFromYAMLNode converts a node selected by the RFC 9535 engine back into the generic tree
+func valueFromYAMLNode(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		object := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := valueFromYAMLNode(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[node.Content[i].Value] = value
+		}
+		return object, nil
+	case yaml.SequenceNode:
+		items := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := valueFromYAMLNode(child)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!str":
+			return node.Value, nil
+		case "!!bool":
+			return node.Value == "true", nil
+		case "!!int", "!!float":
+			return strconv.ParseFloat(node.Value, 64)
+		case "!!null":
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported node in query result: %s", node.Tag)
+}
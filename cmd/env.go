@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	envExport   bool
+	envPrefix   string
+	envKeepCase bool
+)
+
+// envUnsafeKeyChars matches runs of characters that are not allowed in variable names
+var envUnsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// envSafeValue matches values that need no quoting in a shell or dotenv file
+var envSafeValue = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+func init() {
+	resultFlags.BoolVar(&envExport, "env-export", false, "Prefix env output lines with 'export'")
+	resultFlags.StringVar(&envPrefix, "env-prefix", "", "Prefix prepended to every env output variable name")
+	resultFlags.BoolVar(&envKeepCase, "env-keep-case", false, "Keep the case of env output variable names instead of uppercasing them")
+}
+
+// formatEnv flattens an object into KEY=value lines suitable for sourcing or dotenv files
+func formatEnv(data interface{}) ([]byte, error) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("env output requires an object, got %s", jsonTypeName(data))
+	}
+
+	flat, err := flattenObject(object)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0, len(flat))
+	for key, value := range flat {
+		line := envVariableName(key) + "=" + shellQuote(cellText(value))
+		if envExport {
+			line = "export " + line
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// envVariableName turns a flattened key into a valid variable name
+func envVariableName(key string) string {
+	name := strings.Trim(envUnsafeKeyChars.ReplaceAllString(envPrefix+key, "_"), "_")
+	if !envKeepCase {
+		name = strings.ToUpper(name)
+	}
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// shellQuote single-quotes a value when it contains characters the shell would interpret
+func shellQuote(value string) string {
+	if envSafeValue.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
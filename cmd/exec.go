@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runCommand runs an external tool and returns its stdout, including stderr in any error
+func runCommand(name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	command := exec.Command(name, args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("Error running %s: %s", name, msg)
+		}
+		return nil, fmt.Errorf("Error running %s: %v", name, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// runLinesCommand runs an external tool and returns its non-empty output lines
+func runLinesCommand(name string, args ...string) ([]string, error) {
+	output, err := runCommand(name, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
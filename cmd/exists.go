@@ -0,0 +1,38 @@
+package cmd
+
+import "fmt"
+
+var checkExists bool
+
+// noMatch is the fallback --exists queries with, so a result of noMatch means the query
+// matched nothing
+type noMatch struct{}
+
+func init() {
+	readCmd.Flags().BoolVar(&checkExists, "exists", false, "Print nothing and exit with status 0 if the query matches and 1 if it does not")
+}
+
+// validateExists rejects flags --exists cannot be combined with
+func validateExists(hasDefault bool, expressions []string) error {
+	switch {
+	case hasDefault:
+		return fmt.Errorf("Use either --exists or --default, not both")
+	case len(expressions) > 1:
+		return fmt.Errorf("--exists takes a single query")
+	case isStreamingSource() || isArchivePath(filePath) || sqlitePath != "" || postgresDSN != "":
+		return fmt.Errorf("--exists requires a single document source")
+	}
+	return nil
+}
+
+// existsQuery runs a query compiled with a noMatch fallback, reporting a miss as errFalseResult
+func existsQuery(query documentQuery, jsonData interface{}) error {
+	result, err := query(jsonData)
+	if err != nil {
+		return err
+	}
+	if _, missing := result.(noMatch); missing {
+		return errFalseResult
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain <jsonpath>",
+	Short: "Show how a JSONPath expression is parsed and evaluated, step by step",
+	Long: `Show how a JSONPath expression is parsed and evaluated, step by step. With -f,
+each step also shows how many values it matched in the document:
+
+  $ mycli explain -f books.json '$..book[?(@.price < 10)].title'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPath := strings.Trim(args[0], "\"")
+		segments, err := parsePathSegments(jsonPath)
+		if err != nil {
+			return fmt.Errorf("Error parsing JSONPath: %v", err)
+		}
+
+		var jsonData interface{}
+		withCounts := filePath != ""
+		if withCounts {
+			if jsonData, err = loadDocument(); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintln(output, jsonPath)
+		writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "  0.\t$\tthe document root")
+		if withCounts {
+			fmt.Fprintf(writer, "\t1 match")
+		}
+		fmt.Fprintln(writer)
+
+		current := []location{{}}
+		for i, segment := range segments {
+			fmt.Fprintf(writer, "  %d.\t%s\t%s", i+1, segment.text, describeSegment(segment))
+			if withCounts {
+				if current, err = stepLocations(jsonData, current, segment); err != nil {
+					writer.Flush()
+					return jsonPathError(jsonPath, err)
+				}
+				fmt.Fprintf(writer, "\t%s", matchCount(len(current)))
+			}
+			fmt.Fprintln(writer)
+		}
+		return writer.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringVarP(&filePath, "file", "f", "", "JSON file to count the matches of each step in")
+	explainCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	explainCmd.ValidArgsFunction = jsonPathCompletion
+}
+
+// describeSegment explains in words what one step of a path selects
+func describeSegment(segment pathSegment) string {
+	var description string
+	switch segment.kind {
+	case selectKeys:
+		quoted := make([]string, len(segment.keys))
+		for i, key := range segment.keys {
+			quoted[i] = strconv.Quote(key)
+		}
+		if len(quoted) == 1 {
+			description = "the member named " + quoted[0]
+		} else {
+			description = "the members named " + strings.Join(quoted, ", ")
+		}
+	case selectIndices:
+		indices := make([]string, len(segment.indices))
+		for i, index := range segment.indices {
+			indices[i] = strconv.Itoa(index)
+			if index < 0 {
+				indices[i] += " (from the end)"
+			}
+		}
+		if len(indices) == 1 {
+			description = "the array element at index " + indices[0]
+		} else {
+			description = "the array elements at indices " + strings.Join(indices, ", ")
+		}
+	case selectSlice:
+		description = "the array elements " + describeSlice(segment.slice)
+	case selectWildcard:
+		description = "every member or array element"
+	case selectFilter:
+		description = "each member or element for which " + strings.TrimSpace(segment.filter) + " is true"
+	}
+
+	if segment.recursive {
+		return description + ", at any depth"
+	}
+	return description
+}
+
+// matchCount describes the number of values a step matched
+func matchCount(count int) string {
+	if count == 1 {
+		return "1 match"
+	}
+	return strconv.Itoa(count) + " matches"
+}
+
+// describeSlice explains the range of a [start:end:step] slice
+func describeSlice(slice [3]*int) string {
+	bound := func(p *int, fallback string) string {
+		if p == nil {
+			return fallback
+		}
+		if *p < 0 {
+			return strconv.Itoa(-*p) + " from the end"
+		}
+		return "index " + strconv.Itoa(*p)
+	}
+
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+	if step < 0 {
+		description := "from " + bound(slice[0], "the end") + " back to before " + bound(slice[1], "the start")
+		if step != -1 {
+			description += ", every " + strconv.Itoa(-step) + " elements"
+		}
+		return description
+	}
+
+	description := "from " + bound(slice[0], "the start") + " up to before " + bound(slice[1], "the end")
+	if step != 1 {
+		description += ", every " + strconv.Itoa(step) + " elements"
+	}
+	return description
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var flattenSeparator string
+
+// flattenCmd represents the flatten command
+var flattenCmd = &cobra.Command{
+	Use:   "flatten [jsonpath]",
+	Short: "Flatten a document into a single-level object",
+	Long: `Flatten a document, or the value at a path, into a single-level object whose keys join
+the nested object keys with --separator and array indices with brackets. Empty objects and
+arrays are kept as values, as is everything nested deeper than --max-depth:
+
+  $ mycli flatten -f config.json
+  $ mycli flatten -f config.json --separator __ -o env`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+		value, err := queryArgument(jsonData, args)
+		if err != nil {
+			return err
+		}
+		if isScalar(value) {
+			return fmt.Errorf("flatten requires an object or array, got %s", jsonTypeName(value))
+		}
+		flat, err := flattenWith(value, flattenSeparator)
+		if err != nil {
+			return err
+		}
+		return prettyPrintJSON(flat)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(flattenCmd)
+	addResultFlags(flattenCmd)
+
+	flattenCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	flattenCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	flattenCmd.Flags().StringVar(&flattenSeparator, "separator", ".", "Text joining nested object keys")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var inputFormat string
+
+// documentDecoder parses raw file contents into the generic JSON tree
+type documentDecoder func(data []byte) (interface{}, error)
+
+// inputDecoders maps each supported --format value to its decoder
+var inputDecoders = map[string]documentDecoder{
+	"json":       parseJSON,
+	"hcl":        parseHCL,
+	"env":        parseDotenv,
+	"properties": parseProperties,
+	"xlsx":       parseXLSX,
+	"ini":        parseINI,
+}
+
+// formatExtensions maps file extensions to the format they are decoded with
+var formatExtensions = map[string]string{
+	".json":       "json",
+	".tfstate":    "json",
+	".hcl":        "hcl",
+	".tf":         "hcl",
+	".env":        "env",
+	".properties": "properties",
+	".xlsx":       "xlsx",
+	".ini":        "ini",
+	".cfg":        "ini",
+}
+
+func init() {
+	readCmd.Flags().StringVar(&inputFormat, "format", "", "Input format of the file (detected from the extension by default)")
+	readCmd.RegisterFlagCompletionFunc("format", formatCompletion)
+}
+
+// formatForPath returns the format named by --format or implied by the file name, or "" if unknown
+func formatForPath(path string) string {
+	if inputFormat != "" {
+		return inputFormat
+	}
+	if format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	if strings.HasPrefix(filepath.Base(path), ".env") {
+		// Variants such as .env.local and .env.production
+		return "env"
+	}
+	return ""
+}
+
+// decoderForPath picks the decoder from --format, falling back to the file extension and then JSON
+func decoderForPath(path string) (documentDecoder, error) {
+	format := formatForPath(path)
+	if format == "" {
+		format = "json"
+	}
+
+	decoder, ok := inputDecoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported input format: %s", format)
+	}
+	return decoder, nil
+}
+
+// formatCompletion suggests the supported input formats
+func formatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := make([]string, 0, len(inputDecoders))
+	for format := range inputDecoders {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// gronIdentifier matches keys that can be written with dot notation
+var gronIdentifier = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+
+// formatGron renders every node as a greppable `json.path = value;` assignment
+func formatGron(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeGron(&buf, "json", data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeGron writes the assignment for a node followed by those of its children, with keys sorted
+func writeGron(buf *bytes.Buffer, path string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		buf.WriteString(path + " = {};\n")
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := writeGron(buf, gronChildPath(path, key), v[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		buf.WriteString(path + " = [];\n")
+		for i, item := range v {
+			if err := writeGron(buf, path+"["+strconv.Itoa(i)+"]", item); err != nil {
+				return err
+			}
+		}
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(path + " = " + string(encoded) + ";\n")
+	}
+	return nil
+}
+
+// gronChildPath appends an object key using dot notation when possible and bracket notation otherwise
+func gronChildPath(path string, key string) string {
+	if gronIdentifier.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
+
+var ungronFile string
+
+// ungronCmd represents the ungron command
+var ungronCmd = &cobra.Command{
+	Use:   "ungron",
+	Short: "Rebuild a JSON document from gron-style assignments",
+	Long: `Rebuild a JSON document from the assignments printed by "read -o gron",
+typically after filtering them with grep:
+
+  $ mycli read -f doc.json -o gron | grep author | mycli ungron`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := io.Reader(os.Stdin)
+		if ungronFile != "" {
+			file, err := os.Open(ungronFile)
+			if err != nil {
+				return fmt.Errorf("Error reading file: %v", err)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		jsonData, err := ungron(input)
+		if err != nil {
+			return err
+		}
+		return prettyPrintJSON(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ungronCmd)
+	addResultFlags(ungronCmd)
+
+	ungronCmd.Flags().StringVarP(&ungronFile, "file", "f", "", "File of gron assignments (defaults to stdin)")
+}
+
+// ungron parses gron assignments and rebuilds the document they describe
+func ungron(input io.Reader) (interface{}, error) {
+	var root interface{}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		path, rest, err := parseGronPath(line)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing line %d: %v", lineNumber, err)
+		}
+
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, "=") {
+			return nil, fmt.Errorf("Error parsing line %d: expected '='", lineNumber)
+		}
+		rest = strings.TrimSuffix(strings.TrimSpace(rest[1:]), ";")
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(rest), &value); err != nil {
+			return nil, fmt.Errorf("Error parsing line %d: %v", lineNumber, err)
+		}
+
+		root = ungronAssign(root, path, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading input: %v", err)
+	}
+	return root, nil
+}
+
+// parseGronPath parses the `json.key[0]["other key"]` path at the start of a line into
+// string keys and int indices, returning the remainder of the line
+func parseGronPath(line string) ([]interface{}, string, error) {
+	if !strings.HasPrefix(line, "json") {
+		return nil, "", fmt.Errorf("assignment must start with 'json'")
+	}
+	rest := line[len("json"):]
+
+	path := []interface{}{}
+	for {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			end := 1
+			for end < len(rest) && rest[end] != '.' && rest[end] != '[' && rest[end] != ' ' && rest[end] != '=' {
+				end++
+			}
+			if end == 1 {
+				return nil, "", fmt.Errorf("empty key after '.'")
+			}
+			path = append(path, rest[1:end])
+			rest = rest[end:]
+		case strings.HasPrefix(rest, `["`):
+			// Decode the quoted key with a JSON decoder so escapes are honored
+			decoder := json.NewDecoder(strings.NewReader(rest[1:]))
+			var key string
+			if err := decoder.Decode(&key); err != nil {
+				return nil, "", fmt.Errorf("invalid quoted key: %v", err)
+			}
+			rest = rest[1+int(decoder.InputOffset()):]
+			if !strings.HasPrefix(rest, "]") {
+				return nil, "", fmt.Errorf("expected ']' after quoted key")
+			}
+			path = append(path, key)
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, "", fmt.Errorf("expected ']' after index")
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, "", fmt.Errorf("invalid array index %q", rest[1:end])
+			}
+			path = append(path, index)
+			rest = rest[end+1:]
+		default:
+			return path, rest, nil
+		}
+	}
+}
+
+// ungronAssign sets value at path below node, creating objects and arrays along the way
+func ungronAssign(node interface{}, path []interface{}, value interface{}) interface{} {
+	if len(path) == 0 {
+		// Empty containers only declare the node; they never discard children already assigned
+		switch value.(type) {
+		case map[string]interface{}:
+			if _, ok := node.(map[string]interface{}); ok {
+				return node
+			}
+		case []interface{}:
+			if _, ok := node.([]interface{}); ok {
+				return node
+			}
+		}
+		return value
+	}
+
+	switch key := path[0].(type) {
+	case int:
+		array, _ := node.([]interface{})
+		for len(array) <= key {
+			array = append(array, nil)
+		}
+		array[key] = ungronAssign(array[key], path[1:], value)
+		return array
+	default:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			object = make(map[string]interface{})
+		}
+		object[key.(string)] = ungronAssign(object[key.(string)], path[1:], value)
+		return object
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	groupPath         string
+	groupKey          string
+	groupValue        string
+	groupAggregations []string
+)
+
+// groupByCmd represents the group-by command
+var groupByCmd = &cobra.Command{
+	Use:   "group-by",
+	Short: "Group the items of an array by the value at a path within each",
+	Long: `Group the items matched by --path into an object of arrays, keyed by the value --key
+selects in each item. Items without the key are grouped under "null", and keys of different
+types that read the same, such as 1 and "1", are an error rather than one group. With --agg,
+each group is reduced to the aggregation of the numbers --value selects in its items:
+
+  $ mycli group-by -f events.json --path '$[*]' --key '$.region'
+  $ mycli group-by -f bill.json --path '$.lineItems[*]' --key '$.service' --value '$.amount' --agg sum`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if groupKey == "" {
+			return fmt.Errorf("Please specify the path of the grouping value with --key")
+		}
+		if err := validateAggregations(groupAggregations); err != nil {
+			return err
+		}
+		if len(groupAggregations) > 0 && groupValue == "" && !isCountOnly(groupAggregations) {
+			return fmt.Errorf("--agg requires the path of the values to aggregate, given with --value")
+		}
+
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+		items, err := queryArgument(jsonData, []string{groupPath})
+		if err != nil {
+			return err
+		}
+		array, ok := items.([]interface{})
+		if !ok {
+			return fmt.Errorf("group-by requires --path to select an array, got %s", jsonTypeName(items))
+		}
+
+		keyQuery, err := compileQuery(queryLanguage, groupKey, nil, true)
+		if err != nil {
+			return err
+		}
+		valueQuery, err := compileQuery(queryLanguage, groupValue, nil, true)
+		if err != nil {
+			return err
+		}
+		groups := map[string][]interface{}{}
+		keyTypes := map[string]string{}
+		for _, item := range array {
+			key, err := keyQuery(item)
+			if err != nil {
+				return err
+			}
+			// Group names are text, so 1 and "1" would otherwise share a group
+			name := listingText(key)
+			if keyType, seen := keyTypes[name]; seen && keyType != jsonTypeName(key) {
+				return fmt.Errorf("group key %s is a %s in some items and a %s in others", name, keyType, jsonTypeName(key))
+			}
+			keyTypes[name] = jsonTypeName(key)
+			groups[name] = append(groups[name], item)
+		}
+
+		result := make(map[string]interface{}, len(groups))
+		for name, members := range groups {
+			if len(groupAggregations) == 0 {
+				result[name] = members
+				continue
+			}
+			if result[name], err = aggregateGroup(members, valueQuery); err != nil {
+				return fmt.Errorf("%s in group %q", err, name)
+			}
+		}
+		return prettyPrintJSON(result)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(groupByCmd)
+	addResultFlags(groupByCmd)
+
+	groupByCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	groupByCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	groupByCmd.Flags().StringVar(&groupPath, "path", "$", "Path of the items to group")
+	groupByCmd.Flags().StringVar(&groupKey, "key", "", "Path, within each item, of the value to group by")
+	groupByCmd.Flags().StringVar(&groupValue, "value", "", "Path, within each item, of the number to aggregate")
+	groupByCmd.Flags().StringSliceVar(&groupAggregations, "agg", nil, "Reduce each group with sum, avg, min, max or count")
+	groupByCmd.RegisterFlagCompletionFunc("agg", aggregationCompletion)
+}
+
+// isCountOnly reports whether the aggregations only count, which needs no values
+func isCountOnly(names []string) bool {
+	for _, name := range names {
+		if name != "count" {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregateGroup reduces the members of one group with the --agg aggregations. Without
+// --value only counting is possible, and the members themselves are counted.
+func aggregateGroup(members []interface{}, valueQuery documentQuery) (interface{}, error) {
+	values := make([]interface{}, len(members))
+	for i, member := range members {
+		if groupValue == "" {
+			values[i] = 1.0
+			continue
+		}
+		value, err := valueQuery(member)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return aggregate(values, groupAggregations)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// parseHCL decodes an HCL2 file such as Terraform configuration into the generic tree.
+// Blocks nest by type and then by each label; repeated blocks become arrays.
+// Expressions that cannot be evaluated statically (references, function calls) are kept as "${...}" strings.
+func parseHCL(data []byte) (interface{}, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(data, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("Error parsing HCL: %v", diags)
+	}
+
+	return convertHCLBody(file.Body.(*hclsyntax.Body), data)
+}
+
+// convertHCLBody converts the attributes and blocks of a body into an object
+func convertHCLBody(body *hclsyntax.Body, src []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	for name, attr := range body.Attributes {
+		value, err := convertHCLExpression(attr.Expr, src)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+
+	for _, block := range body.Blocks {
+		value, err := convertHCLBody(block.Body, src)
+		if err != nil {
+			return nil, err
+		}
+
+		// Descend through the block type and its labels, creating objects as needed
+		parent := result
+		keys := append([]string{block.Type}, block.Labels...)
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[key] = child
+			}
+			parent = child
+		}
+
+		last := keys[len(keys)-1]
+		switch existing := parent[last].(type) {
+		case nil:
+			parent[last] = value
+		case []interface{}:
+			parent[last] = append(existing, value)
+		default:
+			parent[last] = []interface{}{existing, value}
+		}
+	}
+
+	return result, nil
+}
+
+// convertHCLExpression evaluates a static expression, or returns its source as an interpolation string
+func convertHCLExpression(expr hclsyntax.Expression, src []byte) (interface{}, error) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return "${" + string(expr.Range().SliceBytes(src)) + "}", nil
+	}
+
+	data, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return nil, fmt.Errorf("Error converting HCL value: %v", err)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("Error converting HCL value: %v", err)
+	}
+	return result, nil
+}
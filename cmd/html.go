@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+)
+
+// htmlHeader starts a standalone report page with minimal styling
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>mycli result</title>
+<style>
+body { font-family: monospace; }
+ul { list-style: none; padding-left: 1.5em; margin: 0; }
+summary { cursor: pointer; }
+.key { color: #881391; }
+.string { color: #c41a16; }
+.number, .boolean { color: #1c00cf; }
+.null { color: #808080; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: left; }
+</style>
+</head>
+<body>
+`
+
+// htmlFooter closes the report page
+const htmlFooter = `</body>
+</html>
+`
+
+// formatHTML renders a result as an HTML page: a table for arrays of objects, otherwise a collapsible tree
+func formatHTML(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(htmlHeader)
+
+	if isArrayOfObjects(data) {
+		header, rows, err := tabulate(data)
+		if err != nil {
+			return nil, err
+		}
+		writeHTMLTable(&buf, header, rows)
+	} else {
+		buf.WriteString("<ul>\n")
+		writeHTMLNode(&buf, "$", data)
+		buf.WriteString("</ul>\n")
+	}
+
+	buf.WriteString(htmlFooter)
+	return buf.Bytes(), nil
+}
+
+// isArrayOfObjects reports whether data is a non-empty array whose elements are all objects
+func isArrayOfObjects(data interface{}) bool {
+	items, ok := data.([]interface{})
+	if !ok || len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// writeHTMLTable writes a header and rows as an HTML table
+func writeHTMLTable(buf *bytes.Buffer, header []string, rows [][]string) {
+	buf.WriteString("<table>\n<tr>")
+	for _, column := range header {
+		fmt.Fprintf(buf, "<th>%s</th>", html.EscapeString(column))
+	}
+	buf.WriteString("</tr>\n")
+	for _, row := range rows {
+		buf.WriteString("<tr>")
+		for _, cell := range row {
+			fmt.Fprintf(buf, "<td>%s</td>", html.EscapeString(cell))
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</table>\n")
+}
+
+// writeHTMLNode writes one tree item, nesting objects and arrays inside collapsible sections
+func writeHTMLNode(buf *bytes.Buffer, key string, value interface{}) {
+	label := fmt.Sprintf(`<span class="key">%s</span>`, html.EscapeString(key))
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintf(buf, "<li><details open><summary>%s {%d}</summary><ul>\n", label, len(v))
+		for _, k := range keys {
+			writeHTMLNode(buf, k, v[k])
+		}
+		buf.WriteString("</ul></details></li>\n")
+	case []interface{}:
+		fmt.Fprintf(buf, "<li><details open><summary>%s [%d]</summary><ul>\n", label, len(v))
+		for i, item := range v {
+			writeHTMLNode(buf, fmt.Sprintf("[%d]", i), item)
+		}
+		buf.WriteString("</ul></details></li>\n")
+	default:
+		// Scalars are shown in their JSON form
+		text, err := json.Marshal(v)
+		if err != nil {
+			text = []byte(fmt.Sprint(v))
+		}
+		fmt.Fprintf(buf, `<li>%s: <span class="%s">%s</span></li>`+"\n", label, jsonTypeName(v), html.EscapeString(string(text)))
+	}
+}
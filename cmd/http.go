@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var noCache bool
+
+func init() {
+	readCmd.Flags().BoolVar(&noCache, "no-cache", false, "Always fetch URLs without using or updating the response cache")
+}
+
+// cacheEntry holds the validators for a cached response body
+type cacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// isHTTPURL reports whether the --file value refers to an HTTP(S) resource
+func isHTTPURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// loadURL fetches a document over HTTP(S) and decodes it according to the URL's extension
+func loadURL(rawURL string) (interface{}, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing URL: %v", err)
+	}
+
+	decoder, err := decoderForPath(u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fetchURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(decoder, data)
+}
+
+// fetchURL returns the body at a URL, revalidating any cached copy with a conditional request.
+// If the server cannot be reached the cached copy is used as is, with a warning on stderr.
+func fetchURL(rawURL string) ([]byte, error) {
+	cachePath := ""
+	if !noCache {
+		cachePath = urlCachePath(rawURL)
+	}
+	entry, cached := readCacheEntry(cachePath)
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating request: %v", err)
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := doWithRetries(client, req)
+	if err != nil {
+		if entry != nil {
+			fmt.Fprintf(os.Stderr, "Warning: using cached copy of %s, which may be stale: %v\n", rawURL, err)
+			return cached, nil
+		}
+		return nil, fmt.Errorf("Error fetching URL: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error fetching URL: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching URL: %v", err)
+	}
+
+	// Only responses with validators can be revalidated later
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if cachePath != "" && (etag != "" || lastModified != "") {
+		writeCacheEntry(cachePath, &cacheEntry{URL: rawURL, ETag: etag, LastModified: lastModified}, data)
+	}
+	return data, nil
+}
+
+// urlCachePath returns the cache file prefix for a URL, or "" when no cache directory is available
+func urlCachePath(rawURL string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(dir, "mycli", "http", hex.EncodeToString(sum[:]))
+}
+
+// readCacheEntry loads a cached response body and its validators
+func readCacheEntry(cachePath string) (*cacheEntry, []byte) {
+	if cachePath == "" {
+		return nil, nil
+	}
+
+	meta, err := os.ReadFile(cachePath + ".meta")
+	if err != nil {
+		return nil, nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return nil, nil
+	}
+
+	body, err := os.ReadFile(cachePath + ".body")
+	if err != nil {
+		return nil, nil
+	}
+	return &entry, body
+}
+
+// writeCacheEntry stores a response body and its validators; failures only cost a future refetch
+func writeCacheEntry(cachePath string, entry *cacheEntry, body []byte) {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
+		return
+	}
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(cachePath+".body", body, 0o644); err != nil {
+		return
+	}
+	os.WriteFile(cachePath+".meta", meta, 0o644)
+}
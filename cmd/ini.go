@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseINI decodes an INI file into an object of sections to key/value objects.
+// Keys that appear before the first section header are placed at the top level.
+func parseINI(data []byte) (interface{}, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	result := make(map[string]interface{})
+	section := result
+
+	for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("Error parsing INI: line %d: unterminated section header", i+1)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+
+			// Repeated headers add to the same section
+			existing, ok := result[name].(map[string]interface{})
+			if !ok {
+				existing = make(map[string]interface{})
+				result[name] = existing
+			}
+			section = existing
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			key, value, found = strings.Cut(line, ":")
+		}
+		if !found {
+			return nil, fmt.Errorf("Error parsing INI: line %d: expected key = value", i+1)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		section[strings.TrimSpace(key)] = value
+	}
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	inPlace      bool
+	backupSuffix string
+)
+
+// addInPlaceFlags registers -i/--in-place and --backup, shared by every command that can
+// rewrite the file it reads
+func addInPlaceFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&inPlace, "in-place", "i", false, "Rewrite the file instead of printing the result")
+	cmd.Flags().StringVar(&backupSuffix, "backup", "", "With -i, keep the original file under this suffix (.bak when given without a value)")
+	cmd.Flags().Lookup("backup").NoOptDefVal = ".bak"
+}
+
+// validateInPlace checks that --in-place has a local file to rewrite
+func validateInPlace(path string) error {
+	if backupSuffix != "" && !inPlace {
+		return fmt.Errorf("--backup requires --in-place")
+	}
+	if inPlace && (path == "" || isHTTPURL(path)) {
+		return fmt.Errorf("--in-place requires a local file given with -f")
+	}
+	return nil
+}
+
+// writeInPlace atomically replaces a file's contents, keeping its mode, after copying the
+// original to the --backup file when one was requested
+func writeInPlace(path string, data []byte) error {
+	if backupSuffix != "" {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Error reading file: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("Error reading file: %v", err)
+		}
+		backup := path + backupSuffix
+		if err := writeFileAtomic(backup, original); err != nil {
+			return fmt.Errorf("Error writing backup: %v", err)
+		}
+		if err := os.Chmod(backup, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("Error writing backup: %v", err)
+		}
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("Error writing file: %v", err)
+	}
+	return nil
+}
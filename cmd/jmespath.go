@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmespath/go-jmespath"
+	"github.com/spf13/cobra"
+)
+
+var queryLanguage string
+
+// queryLanguages lists the languages the read argument can be written in
+var queryLanguages = []string{"jsonpath", "jmespath", "jq"}
+
+func init() {
+	readCmd.Flags().StringVar(&queryLanguage, "query-lang", "jsonpath", "Language of the query argument: jsonpath, jmespath or jq")
+	readCmd.RegisterFlagCompletionFunc("query-lang", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return queryLanguages, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// compileJMESPath parses a JMESPath expression once so it can run against every document
+func compileJMESPath(expression string) (*jmespath.JMESPath, error) {
+	compiled, err := jmespath.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing JMESPath: %v", err)
+	}
+	return compiled, nil
+}
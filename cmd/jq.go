@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/itchyny/gojq"
+)
+
+var jqFilter string
+
+func init() {
+	readCmd.Flags().StringVar(&jqFilter, "jq", "", "jq program to run against the document instead of a JSONPath")
+}
+
+// compileJQ parses and compiles a jq program once so it can run against every document,
+// binding --arg and --argjson values as $name variables
+func compileJQ(filter string) (*gojq.Code, []interface{}, error) {
+	variables, err := queryVariables()
+	if err != nil {
+		return nil, nil, err
+	}
+	names := variableNames(variables)
+	values := make([]interface{}, len(names))
+	for i, name := range names {
+		values[i] = variables[name]
+		names[i] = "$" + name
+	}
+
+	parsed, err := gojq.Parse(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error parsing jq program: %v", err)
+	}
+	code, err := gojq.Compile(parsed, gojq.WithVariables(names))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error compiling jq program: %v", err)
+	}
+	return code, values, nil
+}
+
+// runJQ runs a compiled jq program with variable values and collects every value it emits.
+// gojq produces ints and big integers, so each value is converted to the decoded JSON types the
+// output formats expect.
+func runJQ(code *gojq.Code, values []interface{}, jsonData interface{}) ([]interface{}, error) {
+	results := []interface{}{}
+	iter := code.Run(jsonData, values...)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			return results, nil
+		}
+		if err, isErr := value.(error); isErr {
+			if halt, isHalt := err.(*gojq.HaltError); isHalt && halt.Value() == nil {
+				return results, nil
+			}
+			return nil, fmt.Errorf("Error running jq program: %v", err)
+		}
+		value, err := jsonValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("Error running jq program: %v", err)
+		}
+		results = append(results, value)
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// patchOperation is one operation of an RFC 6902 JSON Patch
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  *string     `json:"path"`
+	From  *string     `json:"from"`
+	Value interface{} `json:"value"`
+	// hasValue tells an explicit null value from a missing one
+	hasValue bool
+}
+
+// applyJSONPatch applies the operations of an RFC 6902 patch in order. The patch is all or
+// nothing: the first failing operation is reported and the document is left unchanged.
+func applyJSONPatch(jsonData interface{}, patch interface{}) (interface{}, error) {
+	operations, err := parsePatchOperations(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := copyValue(jsonData)
+	for i, operation := range operations {
+		if result, err = applyPatchOperation(result, operation); err != nil {
+			return nil, fmt.Errorf("Error applying operation %d (%s %s): %v", i, operation.Op, *operation.Path, err)
+		}
+	}
+	return result, nil
+}
+
+// parsePatchOperations checks that a patch is an array of well-formed operations
+func parsePatchOperations(patch interface{}) ([]patchOperation, error) {
+	items, ok := patch.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Error parsing patch: a JSON Patch is an array of operations, got %s (use --merge for a merge patch)", jsonTypeName(patch))
+	}
+
+	operations := make([]patchOperation, len(items))
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Error parsing patch: operation %d is %s, not an object", i, jsonTypeName(item))
+		}
+		encoded, _ := json.Marshal(object)
+		if err := json.Unmarshal(encoded, &operations[i]); err != nil {
+			return nil, fmt.Errorf("Error parsing patch: operation %d: %v", i, err)
+		}
+		_, operations[i].hasValue = object["value"]
+
+		operation := operations[i]
+		switch {
+		case operation.Path == nil:
+			return nil, fmt.Errorf("Error parsing patch: operation %d has no path", i)
+		case (operation.Op == "move" || operation.Op == "copy") && operation.From == nil:
+			return nil, fmt.Errorf("Error parsing patch: %s operation %d has no from", operation.Op, i)
+		case (operation.Op == "add" || operation.Op == "replace" || operation.Op == "test") && !operation.hasValue:
+			return nil, fmt.Errorf("Error parsing patch: %s operation %d has no value", operation.Op, i)
+		}
+	}
+	return operations, nil
+}
+
+// applyPatchOperation applies a single operation, returning the possibly new root
+func applyPatchOperation(jsonData interface{}, operation patchOperation) (interface{}, error) {
+	path := *operation.Path
+	switch operation.Op {
+	case "add":
+		return patchAdd(jsonData, path, operation.Value)
+	case "remove":
+		return patchRemove(jsonData, path)
+	case "replace":
+		loc, err := pointerLocation(jsonData, path)
+		if err != nil {
+			return nil, err
+		}
+		return setAt(jsonData, loc, operation.Value), nil
+	case "move":
+		from := *operation.From
+		if strings.HasPrefix(path, from+"/") {
+			return nil, fmt.Errorf("cannot move %s into its own child", from)
+		}
+		value, err := resolvePointer(jsonData, from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %v", err)
+		}
+		if jsonData, err = patchRemove(jsonData, from); err != nil {
+			return nil, err
+		}
+		return patchAdd(jsonData, path, value)
+	case "copy":
+		value, err := resolvePointer(jsonData, *operation.From)
+		if err != nil {
+			return nil, fmt.Errorf("from: %v", err)
+		}
+		return patchAdd(jsonData, path, copyValue(value))
+	case "test":
+		value, err := resolvePointer(jsonData, path)
+		if err != nil {
+			return nil, fmt.Errorf("test failed: %v", err)
+		}
+		if compareValues(value, operation.Value) != 0 {
+			actual, _ := json.Marshal(value)
+			expected, _ := json.Marshal(operation.Value)
+			return nil, fmt.Errorf("test failed: value is %s, expected %s", actual, expected)
+		}
+		return jsonData, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", operation.Op)
+	}
+}
+
+// patchAdd adds a value at a pointer whose parent exists: it sets an object member, inserts
+// into an array before the indexed element or at the end for '-', or replaces the root
+func patchAdd(jsonData interface{}, pointer string, value interface{}) (interface{}, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	cut := strings.LastIndex(pointer, "/")
+	if cut == -1 {
+		return nil, fmt.Errorf("pointer must be empty or start with '/'")
+	}
+	parentLoc, err := pointerLocation(jsonData, pointer[:cut])
+	if err != nil {
+		return nil, err
+	}
+	token := pointerUnescaper.Replace(pointer[cut+1:])
+
+	parent, _ := getAt(jsonData, parentLoc)
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		return setAt(jsonData, appendLocation(parentLoc, token), value), nil
+	case []interface{}:
+		index := len(v)
+		if token != "-" {
+			if index, err = pointerIndex(token); err != nil {
+				return nil, err
+			}
+			if index > len(v) {
+				return nil, fmt.Errorf("index %d out of bounds for array of length %d", index, len(v))
+			}
+		}
+		return createAt(jsonData, appendLocation(parentLoc, index), value, true)
+	default:
+		return nil, fmt.Errorf("cannot add to %s at %s", jsonTypeName(parent), pointer[:cut])
+	}
+}
+
+// patchRemove removes the member or element a pointer refers to
+func patchRemove(jsonData interface{}, pointer string) (interface{}, error) {
+	loc, err := pointerLocation(jsonData, pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(loc) == 0 {
+		return nil, fmt.Errorf("cannot remove the document root")
+	}
+	return removeAt(jsonData, loc), nil
+}
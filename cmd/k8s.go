@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	k8sResource  string
+	k8sNamespace string
+)
+
+func init() {
+	readCmd.Flags().StringVar(&k8sResource, "k8s", "", "Kubernetes resource (kind/name) to fetch via kubectl and query")
+	readCmd.Flags().StringVarP(&k8sNamespace, "namespace", "n", "", "Kubernetes namespace for --k8s")
+
+	// Complete resource kinds, then names once a kind has been typed
+	readCmd.RegisterFlagCompletionFunc("k8s", k8sResourceCompletion)
+	readCmd.RegisterFlagCompletionFunc("namespace", k8sNamespaceCompletion)
+}
+
+// kubectlArgs appends the --namespace flag when one was given
+func kubectlArgs(args ...string) []string {
+	if k8sNamespace != "" {
+		args = append(args, "--namespace", k8sNamespace)
+	}
+	return args
+}
+
+// loadK8sDocument fetches a live resource using the configured kubeconfig and parses it
+func loadK8sDocument(resource string) (interface{}, error) {
+	output, err := runCommand("kubectl", kubectlArgs("get", resource, "--output", "json")...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(parseJSON, output)
+}
+
+// k8sResourceCompletion suggests resource kinds, or names of the given kind after a '/'
+func k8sResourceCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	kind, _, hasName := strings.Cut(toComplete, "/")
+	if !hasName {
+		kinds, err := runLinesCommand("kubectl", "api-resources", "--verbs", "get", "--output", "name")
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for i, k := range kinds {
+			kinds[i] = k + "/"
+		}
+		return kinds, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, err := runLinesCommand("kubectl", kubectlArgs("get", kind, "--no-headers", "--output", "custom-columns=:metadata.name")...)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	for i, name := range names {
+		names[i] = kind + "/" + name
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// k8sNamespaceCompletion suggests the namespaces of the current cluster
+func k8sNamespaceCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	namespaces, err := runLinesCommand("kubectl", "get", "namespaces", "--no-headers", "--output", "custom-columns=:metadata.name")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return namespaces, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// listingWriter prints one line per member of an object or element of an array
+type listingWriter func(key string, value interface{})
+
+// newListingCommand builds a command that prints a line for each member or element of the
+// value at an optional path
+func newListingCommand(name string, short string, write listingWriter) *cobra.Command {
+	command := &cobra.Command{
+		Use:   name + " [jsonpath]",
+		Short: short,
+		Long: short + `, one per line. Object keys are sorted; array
+elements are listed by index. Strings are printed raw and other values as compact JSON:
+
+  $ mycli ` + name + ` -f deployment.json '$.spec.template.metadata.labels'`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: jsonPathCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonData, err := loadDocumentOrStdin()
+			if err != nil {
+				return err
+			}
+			value, err := queryArgument(jsonData, args)
+			if err != nil {
+				return err
+			}
+
+			switch v := value.(type) {
+			case map[string]interface{}:
+				keys := make([]string, 0, len(v))
+				for key := range v {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					write(key, v[key])
+				}
+			case []interface{}:
+				for i, item := range v {
+					write(strconv.Itoa(i), item)
+				}
+			default:
+				return fmt.Errorf("%s requires an object or array, got %s", name, jsonTypeName(value))
+			}
+			return nil
+		},
+	}
+	command.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	command.RegisterFlagCompletionFunc("file", fileCompletion)
+	return command
+}
+
+func init() {
+	rootCmd.AddCommand(newListingCommand("keys", "List the keys of an object or indices of an array", func(key string, value interface{}) {
+		fmt.Fprintln(output, key)
+	}))
+	rootCmd.AddCommand(newListingCommand("values", "List the values of an object or elements of an array", func(key string, value interface{}) {
+		fmt.Fprintln(output, listingText(value))
+	}))
+	rootCmd.AddCommand(newListingCommand("entries", "List the key and value of each member of an object or element of an array", func(key string, value interface{}) {
+		fmt.Fprintf(output, "%s\t%s\n", key, listingText(value))
+	}))
+}
+
+// queryArgument evaluates the optional path argument of a command, defaulting to the whole document
+func queryArgument(jsonData interface{}, args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return jsonData, nil
+	}
+	query, err := compileQuery(queryLanguage, args[0], nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return query(jsonData)
+}
+
+// listingText renders a value on a single line, keeping strings raw
+func listingText(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	// Embedded newlines would split one value across lines
+	return strings.ReplaceAll(cellText(value), "\n", `\n`)
+}
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// lintFinding is one problem found in a path, at a 1-based column
+type lintFinding struct {
+	column   int
+	severity string
+	message  string
+}
+
+// lintEdit replaces path[start:end] as part of a suggested fix
+type lintEdit struct {
+	start, end  int
+	replacement string
+}
+
+// filterLintRule inspects one filter expression for the selected engine. Offsets are into the
+// filter; masked is the filter with the contents of string and regex literals blanked out
+// so rules only match operators and paths.
+type filterLintRule func(filter string, masked string) ([]lintFinding, []lintEdit)
+
+// filterLintRules lists the filter checks in the order they are reported
+var filterLintRules = []filterLintRule{
+	lintSingleEquals,
+	lintExistenceTest,
+	lintFunctionCalls,
+	lintRegexMatch,
+	lintInOperator,
+}
+
+var (
+	singleEquals       = regexp.MustCompile(`[^=!<>~]=[^=~]`)
+	existenceTest      = regexp.MustCompile(`^(\(\s*)?(!\s*)?(@(?:\.[A-Za-z_$][\w$]*|\[[^\]]*\])+)(\s*\))?$`)
+	filterFunctionCall = regexp.MustCompile(`\b(length|count|match|search|value)\s*\(`)
+	regexMatch         = regexp.MustCompile(`(@[\w$.\[\]]*)\s*=~\s*/(_*)/([ims]*)`)
+	inOperator         = regexp.MustCompile(`\sin\s`)
+	scriptLength       = regexp.MustCompile(`\[\s*\(\s*@\.length\s*-\s*(\d+)\s*\)\s*\]`)
+	rfc9535ParseError  = regexp.MustCompile(`Error at line (\d+), column (\d+): ([^\n]*)`)
+	regexOperator      = regexp.MustCompile(`=~\s*$`)
+)
+
+// lintPathCmd represents the lint-path command
+var lintPathCmd = &cobra.Command{
+	Use:   "lint-path <jsonpath>...",
+	Short: "Check JSONPath expressions for errors and constructs the engine does not support",
+	Long: `Check JSONPath expressions without a document: syntax errors, and constructs the
+selected --engine rejects or silently evaluates differently. A fixed expression is suggested
+where one can be derived. Exits non-zero when any problem is found, for use in CI:
+
+  $ mycli lint-path '$.items[?(@.labels)]'
+  $.items[?(@.labels)]:11: warning: existence tests match nothing with the paessler engine; compare with null instead
+    suggestion: $.items[?(@.labels != null)]`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := selectedEngine(); err != nil {
+			return err
+		}
+
+		problems := 0
+		for _, arg := range args {
+			jsonPath := strings.Trim(arg, "\"")
+			findings, fixed := lintJSONPath(jsonPath)
+			if len(findings) == 0 {
+				fmt.Fprintf(output, "%s: ok\n", jsonPath)
+				continue
+			}
+
+			problems += len(findings)
+			for _, finding := range findings {
+				fmt.Fprintf(output, "%s:%d: %s: %s\n", jsonPath, finding.column, finding.severity, finding.message)
+			}
+			if fixed != jsonPath {
+				fmt.Fprintf(output, "  suggestion: %s\n", fixed)
+			}
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("Found %s", plural(problems, "problem"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintPathCmd)
+}
+
+// lintJSONPath returns the problems found in a path and the path with every available fix applied
+func lintJSONPath(jsonPath string) ([]lintFinding, string) {
+	if !strings.HasPrefix(jsonPath, "$") {
+		fixed := "$." + jsonPath
+		if strings.HasPrefix(jsonPath, ".") || strings.HasPrefix(jsonPath, "[") {
+			fixed = "$" + jsonPath
+		}
+		return []lintFinding{{1, "error", "path must start with '$'"}}, fixed
+	}
+
+	var findings []lintFinding
+	var edits []lintEdit
+
+	// Script expressions are not part of either engine's syntax
+	for _, match := range scriptLength.FindAllStringSubmatchIndex(jsonPath, -1) {
+		findings = append(findings, lintFinding{match[0] + 1, "error", "script expressions are not supported; use a negative index"})
+		edits = append(edits, lintEdit{match[0], match[1], "[-" + jsonPath[match[2]:match[3]] + "]"})
+	}
+
+	for _, span := range filterSpans(jsonPath) {
+		filterFindings, filterEdits := lintFilter(jsonPath[span[0]:span[1]])
+		for _, finding := range filterFindings {
+			finding.column += span[0] + 1
+			findings = append(findings, finding)
+		}
+		for _, edit := range filterEdits {
+			edits = append(edits, lintEdit{edit.start + span[0], edit.end + span[0], edit.replacement})
+		}
+	}
+	ruleErrors := false
+	for _, finding := range findings {
+		ruleErrors = ruleErrors || finding.severity == "error"
+	}
+
+	fixed := applyLintEdits(jsonPath, edits)
+
+	// Report the engine's own syntax error only when the rules neither explain nor fix it
+	err := validateJSONPath(jsonPath)
+	explained := ruleErrors && (fixed == jsonPath || validateJSONPath(fixed) == nil)
+	if err != nil && !explained {
+		findings = append(findings, syntaxFinding(err))
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].column < findings[j].column })
+	return findings, fixed
+}
+
+// filterSpans returns the start and end offsets of the expression inside each [?...] selector.
+// The path is scanned directly because parsing rewrites regex literals and so moves offsets.
+func filterSpans(jsonPath string) [][2]int {
+	var spans [][2]int
+	var quote byte
+	for i := 0; i < len(jsonPath); i++ {
+		c := jsonPath[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			end, err := closingBracket(jsonPath[i:])
+			if err != nil {
+				return spans
+			}
+			content := jsonPath[i+1 : i+end]
+			trimmed := strings.TrimLeft(content, " ")
+			if strings.HasPrefix(trimmed, "?") {
+				start := i + 1 + len(content) - len(trimmed) + 1
+				for start < i+end && jsonPath[start] == ' ' {
+					start++
+				}
+				spans = append(spans, [2]int{start, i + end - len(content) + len(strings.TrimRight(content, " "))})
+			}
+			i += end
+		}
+	}
+	return spans
+}
+
+// lintFilter runs the filter rules, returning findings and edits with offsets into the filter
+func lintFilter(filter string) ([]lintFinding, []lintEdit) {
+	masked := maskLiterals(filter)
+
+	var findings []lintFinding
+	var edits []lintEdit
+	for _, rule := range filterLintRules {
+		ruleFindings, ruleEdits := rule(filter, masked)
+		findings = append(findings, ruleFindings...)
+		edits = append(edits, ruleEdits...)
+	}
+	return findings, edits
+}
+
+// maskLiterals replaces the contents of quoted strings and of regex literals after =~ with
+// underscores, leaving the delimiters and every offset in place
+func maskLiterals(filter string) string {
+	masked := []byte(filter)
+	var closing byte
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		switch {
+		case closing != 0:
+			if c == closing {
+				closing = 0
+				continue
+			}
+			masked[i] = '_'
+			if c == '\\' && i+1 < len(masked) {
+				i++
+				masked[i] = '_'
+			}
+		case c == '\'' || c == '"':
+			closing = c
+		case c == '/' && regexOperator.Match(masked[:i]):
+			closing = '/'
+		}
+	}
+	return string(masked)
+}
+
+// applyLintEdits applies non-overlapping edits to a path in order of position
+func applyLintEdits(jsonPath string, edits []lintEdit) string {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, edit := range edits {
+		if edit.start < last {
+			continue
+		}
+		b.WriteString(jsonPath[last:edit.start])
+		b.WriteString(edit.replacement)
+		last = edit.end
+	}
+	b.WriteString(jsonPath[last:])
+	return b.String()
+}
+
+// syntaxFinding reports an engine's parse error at the column it names
+func syntaxFinding(err error) lintFinding {
+	if match := gvalParseError.FindStringSubmatch(err.Error()); match != nil {
+		column := match[2]
+		if column == "" {
+			column = match[4]
+		}
+		col, _ := strconv.Atoi(column)
+		return lintFinding{col, "error", match[5]}
+	}
+	// Nested parse errors repeat the position; the innermost message is the most specific
+	if matches := rfc9535ParseError.FindAllStringSubmatch(err.Error(), -1); matches != nil {
+		match := matches[len(matches)-1]
+		col, _ := strconv.Atoi(match[2])
+		return lintFinding{col, "error", match[3]}
+	}
+	return lintFinding{1, "error", err.Error()}
+}
+
+// lintSingleEquals flags = used for comparison
+func lintSingleEquals(filter string, masked string) ([]lintFinding, []lintEdit) {
+	var findings []lintFinding
+	var edits []lintEdit
+	for _, match := range singleEquals.FindAllStringIndex(masked, -1) {
+		at := match[0] + 1
+		findings = append(findings, lintFinding{at, "error", "'=' is not a comparison; use '=='"})
+		edits = append(edits, lintEdit{at, at + 1, "=="})
+	}
+	return findings, edits
+}
+
+// lintExistenceTest flags filters that only name a path, which PaesslerAG requires to be boolean
+func lintExistenceTest(filter string, masked string) ([]lintFinding, []lintEdit) {
+	if jsonPathEngineName != "paessler" {
+		return nil, nil
+	}
+	match := existenceTest.FindStringSubmatchIndex(masked)
+	if match == nil {
+		return nil, nil
+	}
+
+	if match[4] != -1 {
+		return []lintFinding{{0, "warning", "negated existence tests match every value with the paessler engine; use --engine rfc9535"}}, nil
+	}
+	path := filter[match[6]:match[7]]
+	return []lintFinding{{match[6], "warning", "existence tests match nothing with the paessler engine; compare with null instead"}},
+		[]lintEdit{{match[6], match[7], path + " != null"}}
+}
+
+// lintFunctionCalls flags the RFC 9535 filter functions, which PaesslerAG does not provide
+func lintFunctionCalls(filter string, masked string) ([]lintFinding, []lintEdit) {
+	if jsonPathEngineName != "paessler" {
+		return nil, nil
+	}
+
+	var findings []lintFinding
+	for _, match := range filterFunctionCall.FindAllStringSubmatchIndex(masked, -1) {
+		name := filter[match[2]:match[3]]
+		findings = append(findings, lintFinding{match[0], "warning", fmt.Sprintf("%s() is not supported by the paessler engine and matches nothing; use --engine rfc9535", name)})
+	}
+	return findings, nil
+}
+
+// lintRegexMatch flags the =~ operator, which RFC 9535 replaces with search()
+func lintRegexMatch(filter string, masked string) ([]lintFinding, []lintEdit) {
+	if jsonPathEngineName != "rfc9535" {
+		return nil, nil
+	}
+
+	var findings []lintFinding
+	var edits []lintEdit
+	for _, match := range regexMatch.FindAllStringSubmatchIndex(masked, -1) {
+		findings = append(findings, lintFinding{match[0], "error", "=~ is not supported by the rfc9535 engine; use search() or match()"})
+		// I-Regexp has no flags, so only plain patterns can be rewritten
+		if match[6] == match[7] {
+			operand := filter[match[2]:match[3]]
+			pattern := strings.ReplaceAll(filter[match[4]:match[5]], `'`, `\'`)
+			edits = append(edits, lintEdit{match[0], match[1], "search(" + operand + ", '" + pattern + "')"})
+		}
+	}
+	return findings, edits
+}
+
+// lintInOperator flags the in operator, which RFC 9535 does not define
+func lintInOperator(filter string, masked string) ([]lintFinding, []lintEdit) {
+	if jsonPathEngineName != "rfc9535" {
+		return nil, nil
+	}
+
+	var findings []lintFinding
+	for _, match := range inOperator.FindAllStringIndex(masked, -1) {
+		findings = append(findings, lintFinding{match[0] + 1, "error", "the in operator is not supported by the rfc9535 engine; combine == comparisons with ||"})
+	}
+	return findings, nil
+}
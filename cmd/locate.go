@@ -0,0 +1,802 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ignoreCase bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&ignoreCase, "ignore-case", false, "Match object keys in paths, including those filters use, and search terms case-insensitively")
+}
+
+// location is the concrete path to a node: string keys for objects and int indices for arrays
+type location []interface{}
+
+// segmentKind identifies the selector used by one step of a JSONPath
+type segmentKind int
+
+const (
+	selectKeys segmentKind = iota
+	selectIndices
+	selectWildcard
+	selectSlice
+	selectFilter
+)
+
+// pathSegment is one parsed step of a JSONPath expression
+type pathSegment struct {
+	recursive    bool
+	kind         segmentKind
+	keys         []string
+	indices      []int
+	slice        [3]*int
+	filter       string
+	singleQuoted bool
+	text         string
+}
+
+// String renders a location as a normalized JSONPath
+func (loc location) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, key := range loc {
+		switch k := key.(type) {
+		case int:
+			b.WriteString("[" + strconv.Itoa(k) + "]")
+		case string:
+			if gronIdentifier.MatchString(k) {
+				b.WriteString("." + k)
+			} else {
+				b.WriteString("[" + strconv.Quote(k) + "]")
+			}
+		}
+	}
+	return b.String()
+}
+
+// parsePathSegments splits a JSONPath into segments
+func parsePathSegments(jsonPath string) ([]pathSegment, error) {
+	if !strings.HasPrefix(jsonPath, "$") {
+		return nil, fmt.Errorf("path must start with '$'")
+	}
+	jsonPath, err := rewriteRegexLiterals(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := []pathSegment{}
+	rest := jsonPath[1:]
+	for rest != "" {
+		start := rest
+		recursive := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			recursive = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "["):
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", rest[0], len(jsonPath)-len(rest))
+		}
+
+		var segment pathSegment
+		if strings.HasPrefix(rest, "[") {
+			end, err := closingBracket(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%v at offset %d", err, len(jsonPath)-len(rest))
+			}
+			segment, err = parseBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%v at offset %d", err, len(jsonPath)-len(rest))
+			}
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("missing name at offset %d", len(jsonPath)-len(rest))
+			}
+			if name == "*" {
+				segment = pathSegment{kind: selectWildcard}
+			} else {
+				segment = pathSegment{kind: selectKeys, keys: []string{name}}
+			}
+			rest = rest[end:]
+		}
+
+		segment.recursive = recursive
+		segment.text = start[:len(start)-len(rest)]
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// closingBracket finds the ']' matching the '[' at the start of s, skipping quotes and parentheses
+func closingBracket(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ']' && depth == 0:
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated '['")
+}
+
+// parseBracket parses the selector inside brackets: a filter, wildcard, slice or union of keys/indices
+func parseBracket(content string) (pathSegment, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(content, "?"):
+		return pathSegment{kind: selectFilter, filter: strings.TrimSpace(content[1:])}, nil
+	case content == "*":
+		return pathSegment{kind: selectWildcard}, nil
+	case content == "":
+		return pathSegment{}, fmt.Errorf("empty brackets")
+	}
+
+	parts := splitUnion(content)
+	if len(parts) == 1 && !isQuoted(parts[0]) && strings.Contains(parts[0], ":") {
+		return parseSlice(parts[0])
+	}
+
+	segment := pathSegment{}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if isQuoted(part) {
+			segment.singleQuoted = segment.singleQuoted || part[0] == '\''
+			key, err := unquoteKey(part)
+			if err != nil {
+				return pathSegment{}, err
+			}
+			segment.keys = append(segment.keys, key)
+			continue
+		}
+		index, err := strconv.Atoi(part)
+		if err != nil {
+			return pathSegment{}, fmt.Errorf("invalid index %q", part)
+		}
+		segment.indices = append(segment.indices, index)
+	}
+
+	switch {
+	case len(segment.keys) > 0 && len(segment.indices) > 0:
+		return pathSegment{}, fmt.Errorf("cannot mix keys and indices in a union")
+	case len(segment.keys) > 0:
+		segment.kind = selectKeys
+	default:
+		segment.kind = selectIndices
+	}
+	return segment, nil
+}
+
+// splitUnion splits bracket content on commas outside of quotes
+func splitUnion(content string) []string {
+	parts := []string{}
+	var quote byte
+	start := 0
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			parts = append(parts, content[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, content[start:])
+}
+
+// isQuoted reports whether s is a single- or double-quoted string
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// unquoteKey decodes a quoted bracket key, accepting single or double quotes
+func unquoteKey(s string) (string, error) {
+	if s[0] == '\'' {
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	key, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted key %s", s)
+	}
+	return key, nil
+}
+
+// parseSlice parses a [start:end:step] slice where every part is optional
+func parseSlice(content string) (pathSegment, error) {
+	parts := strings.Split(content, ":")
+	if len(parts) > 3 {
+		return pathSegment{}, fmt.Errorf("invalid slice %q", content)
+	}
+
+	segment := pathSegment{kind: selectSlice}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return pathSegment{}, fmt.Errorf("invalid slice %q", content)
+		}
+		segment.slice[i] = &n
+	}
+	if segment.slice[2] != nil && *segment.slice[2] == 0 {
+		return pathSegment{}, fmt.Errorf("slice step cannot be zero")
+	}
+	return segment, nil
+}
+
+// sliceIndices returns the indices a slice selects from an array of the given length
+func sliceIndices(slice [3]*int, length int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+
+	// Resolve negative and missing bounds the way Python slices do
+	bound := func(p *int, fallback int) int {
+		if p == nil {
+			return fallback
+		}
+		n := *p
+		if n < 0 {
+			n += length
+		}
+		low, high := 0, length
+		if step < 0 {
+			low, high = -1, length-1
+		}
+		if n < low {
+			n = low
+		}
+		if n > high {
+			n = high
+		}
+		return n
+	}
+
+	indices := []int{}
+	if step > 0 {
+		for i := bound(slice[0], 0); i < bound(slice[1], length); i += step {
+			indices = append(indices, i)
+		}
+	} else {
+		for i := bound(slice[0], length-1); i > bound(slice[1], -1); i += step {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// The locator evaluates JSONPath itself, keeping track of where each match is so commands can
+// change the document there. It also answers queries the engines cannot: see the read command's
+// help for when it is used in place of the selected engine.
+
+// locateJSONPath returns the locations of every node a JSONPath selects, in document order
+func locateJSONPath(data interface{}, jsonPath string) ([]location, error) {
+	locations := []location{}
+	err := walkJSONPath(data, jsonPath, func(loc location, value interface{}) error {
+		locations = append(locations, loc)
+		return nil
+	})
+	return locations, err
+}
+
+// walkJSONPath calls visit for each node a JSONPath selects as soon as it is found,
+// stopping at the first error visit returns
+func walkJSONPath(data interface{}, jsonPath string, visit func(loc location, value interface{}) error) error {
+	segments, err := parsePathSegments(jsonPath)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %v", jsonPath, err)
+	}
+
+	var walk func(loc location, node interface{}, segments []pathSegment) error
+	walk = func(loc location, node interface{}, segments []pathSegment) error {
+		if len(segments) == 0 {
+			return visit(loc, node)
+		}
+
+		segment := segments[0]
+		candidates := []location{loc}
+		if segment.recursive {
+			candidates = descendants(node, loc)
+		}
+		for _, candidate := range candidates {
+			value, _ := getAt(node, candidate[len(loc):])
+			matches, err := selectChildren(data, value, candidate, segment)
+			if err != nil {
+				return err
+			}
+			for _, match := range matches {
+				child, _ := getAt(node, match[len(loc):])
+				if err := walk(match, child, segments[1:]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return walk(location{}, data, segments)
+}
+
+// stepLocations applies one segment to every location matched so far
+func stepLocations(data interface{}, current []location, segment pathSegment) ([]location, error) {
+	next := []location{}
+	for _, loc := range current {
+		node, _ := getAt(data, loc)
+		candidates := []location{loc}
+		if segment.recursive {
+			candidates = descendants(node, loc)
+		}
+		for _, candidate := range candidates {
+			value, _ := getAt(data, candidate)
+			matches, err := selectChildren(data, value, candidate, segment)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, matches...)
+		}
+	}
+	return next, nil
+}
+
+// descendants returns the location of a node and of every node below it
+func descendants(node interface{}, loc location) []location {
+	result := []location{loc}
+	for _, child := range childLocations(node, loc) {
+		value, _ := getAt(node, child[len(loc):])
+		result = append(result, descendants(value, child)...)
+	}
+	return result
+}
+
+// childLocations lists the locations of a node's direct children, with object keys sorted
+func childLocations(node interface{}, loc location) []location {
+	children := []location{}
+	switch v := node.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			children = append(children, appendLocation(loc, key))
+		}
+	case []interface{}:
+		for i := range v {
+			children = append(children, appendLocation(loc, i))
+		}
+	}
+	return children
+}
+
+// selectChildren applies one segment's selector to a node of the document root
+func selectChildren(root interface{}, node interface{}, loc location, segment pathSegment) ([]location, error) {
+	matches := []location{}
+	switch segment.kind {
+	case selectKeys:
+		if object, ok := node.(map[string]interface{}); ok {
+			for _, key := range segment.keys {
+				if _, exists := object[key]; exists && !ignoreCase {
+					matches = append(matches, appendLocation(loc, key))
+					continue
+				}
+				if ignoreCase {
+					// Every key differing only in case matches, in sorted order
+					for _, child := range childLocations(object, loc) {
+						if strings.EqualFold(child[len(child)-1].(string), key) {
+							matches = append(matches, child)
+						}
+					}
+				}
+			}
+		}
+	case selectIndices:
+		if array, ok := node.([]interface{}); ok {
+			for _, index := range segment.indices {
+				if index < 0 {
+					index += len(array)
+				}
+				if index >= 0 && index < len(array) {
+					matches = append(matches, appendLocation(loc, index))
+				}
+			}
+		}
+	case selectSlice:
+		if array, ok := node.([]interface{}); ok {
+			for _, index := range sliceIndices(segment.slice, len(array)) {
+				matches = append(matches, appendLocation(loc, index))
+			}
+		}
+	case selectWildcard:
+		matches = childLocations(node, loc)
+	case selectFilter:
+		for _, child := range childLocations(node, loc) {
+			value, _ := getAt(node, child[len(loc):])
+			ok, err := matchesFilter(root, value, segment.filter)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matches = append(matches, child)
+			}
+		}
+	}
+	return matches, nil
+}
+
+// matchesFilter evaluates a filter expression against one candidate using the query engine.
+// The engine only sees the candidate, so paths from the document root are resolved first.
+func matchesFilter(root interface{}, candidate interface{}, filter string) (bool, error) {
+	engine, err := selectedEngine()
+	if err != nil {
+		return false, err
+	}
+	filter, err = inlineRootPaths(root, filter)
+	if err != nil {
+		if isMissingPathError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if ignoreCase {
+		candidate = aliasFoldedKeys(candidate, filterKeyNames(filter))
+	}
+	result, err := engine("$[?"+filter+"]", []interface{}{candidate})
+	if err != nil {
+		if isMissingPathError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	items, ok := result.([]interface{})
+	return ok && len(items) > 0, nil
+}
+
+// filterKeyNames lists the member names a filter refers to, written as .name or ['name']
+func filterKeyNames(filter string) []string {
+	names := []string{}
+	for i := 0; i < len(filter); i++ {
+		switch c := filter[i]; {
+		case c == '\'' || c == '"':
+			// Skip string literals that are compared against rather than used as keys
+			end := i + 1
+			for end < len(filter) && filter[end] != c {
+				if filter[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			i = end
+		case c == '.':
+			end := i + 1
+			for end < len(filter) && isKeyChar(filter[end]) {
+				end++
+			}
+			if end > i+1 {
+				names = append(names, filter[i+1:end])
+			}
+			i = end - 1
+		case c == '[':
+			closing, err := closingBracket(filter[i:])
+			if err != nil {
+				continue
+			}
+			for _, part := range splitUnion(filter[i+1 : i+closing]) {
+				if key, err := unquoteKey(strings.TrimSpace(part)); err == nil {
+					names = append(names, key)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// isKeyChar reports whether c can appear in a member name written after a dot
+func isKeyChar(c byte) bool {
+	return c == '_' || c == '-' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// aliasFoldedKeys copies a value, adding each of the names that is missing from an object under
+// that spelling when a member differing only in case exists, so --ignore-case also applies to the
+// keys a filter uses. The first such member in sorted order is used.
+func aliasFoldedKeys(value interface{}, names []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			object[key] = aliasFoldedKeys(child, names)
+		}
+		for _, name := range names {
+			if _, exists := object[name]; exists {
+				continue
+			}
+			for _, child := range childLocations(v, location{}) {
+				if key := child[0].(string); strings.EqualFold(key, name) {
+					object[name] = object[key]
+					break
+				}
+			}
+		}
+		return object
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, child := range v {
+			items[i] = aliasFoldedKeys(child, names)
+		}
+		return items
+	}
+	return value
+}
+
+// inlineRootPaths replaces each path starting at $ in a filter with the JSON literal of the
+// value it selects from the document root. A path that selects nothing is reported as a missing
+// path, so the filter matches nothing, as it does when the engine evaluates it over the whole document.
+func inlineRootPaths(root interface{}, filter string) (string, error) {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(filter); i++ {
+		c := filter[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(filter) {
+				b.WriteByte(c)
+				i++
+				c = filter[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(filter[i:], "=~"):
+			// Copy a /regex/ literal whole, since it may contain $
+			end := i + 2
+			for end < len(filter) && filter[end] == ' ' {
+				end++
+			}
+			if end < len(filter) && filter[end] == '/' {
+				for end++; end < len(filter) && filter[end] != '/'; end++ {
+					if filter[end] == '\\' {
+						end++
+					}
+				}
+				b.WriteString(filter[i:min(end+1, len(filter))])
+				i = end
+				continue
+			}
+		case c == '$':
+			end := rootPathEnd(filter, i)
+			value, err := queryJSONPath(root, filter[i:end])
+			if err != nil {
+				return "", err
+			}
+			literal, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			b.Write(literal)
+			i = end - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// rootPathEnd returns the index just past the path that starts with the $ at start
+func rootPathEnd(filter string, start int) int {
+	end := start + 1
+	for end < len(filter) {
+		switch c := filter[end]; {
+		case c == '.' || c == '*' || isKeyChar(c):
+			end++
+		case c == '[':
+			closing, err := closingBracket(filter[end:])
+			if err != nil {
+				return end
+			}
+			end += closing + 1
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// appendLocation returns a new location extending loc by one key
+func appendLocation(loc location, key interface{}) location {
+	result := make(location, len(loc), len(loc)+1)
+	copy(result, loc)
+	return append(result, key)
+}
+
+// getAt returns the node at a location and whether it exists
+func getAt(data interface{}, loc location) (interface{}, bool) {
+	node := data
+	for _, key := range loc {
+		switch k := key.(type) {
+		case string:
+			object, ok := node.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if node, ok = object[k]; !ok {
+				return nil, false
+			}
+		case int:
+			array, ok := node.([]interface{})
+			if !ok || k < 0 || k >= len(array) {
+				return nil, false
+			}
+			node = array[k]
+		}
+	}
+	return node, true
+}
+
+// setAt replaces the node at an existing location, returning the possibly new root
+func setAt(data interface{}, loc location, value interface{}) interface{} {
+	if len(loc) == 0 {
+		return value
+	}
+	parent, _ := getAt(data, loc[:len(loc)-1])
+	switch k := loc[len(loc)-1].(type) {
+	case string:
+		parent.(map[string]interface{})[k] = value
+	case int:
+		parent.([]interface{})[k] = value
+	}
+	return data
+}
+
+// singularLocation returns the location a path of plain keys and non-negative indices names,
+// whether or not it exists in a document
+func singularLocation(jsonPath string) (location, bool) {
+	segments, err := parsePathSegments(jsonPath)
+	if err != nil {
+		return nil, false
+	}
+
+	loc := location{}
+	for _, segment := range segments {
+		switch {
+		case segment.recursive:
+			return nil, false
+		case segment.kind == selectKeys && len(segment.keys) == 1:
+			loc = append(loc, segment.keys[0])
+		case segment.kind == selectIndices && len(segment.indices) == 1 && segment.indices[0] >= 0:
+			loc = append(loc, segment.indices[0])
+		default:
+			return nil, false
+		}
+	}
+	return loc, true
+}
+
+// createAt sets the value at a location, creating missing objects along the way. An index
+// one past the end of an array appends to it; with insert, the value is inserted before the
+// element at the final index rather than replacing it, as a JSON Patch add does.
+func createAt(data interface{}, loc location, value interface{}, insert bool) (interface{}, error) {
+	if len(loc) == 0 {
+		return value, nil
+	}
+
+	switch k := loc[0].(type) {
+	case string:
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot set key %q in %s", k, jsonTypeName(data))
+		}
+		child, err := createAt(object[k], loc[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		object[k] = child
+		return object, nil
+	default:
+		index := k.(int)
+		array, ok := data.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot set index %d in %s", index, jsonTypeName(data))
+		}
+		if index > len(array) {
+			return nil, fmt.Errorf("index %d out of bounds for array of length %d", index, len(array))
+		}
+		if len(loc) == 1 && insert {
+			return append(array[:index], append([]interface{}{value}, array[index:]...)...), nil
+		}
+		if index == len(array) {
+			array = append(array, nil)
+		}
+		child, err := createAt(array[index], loc[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		array[index] = child
+		return array, nil
+	}
+}
+
+// removeAt deletes the member or element at an existing location, returning the possibly new root
+func removeAt(data interface{}, loc location) interface{} {
+	parentLoc := loc[:len(loc)-1]
+	parent, _ := getAt(data, parentLoc)
+	switch k := loc[len(loc)-1].(type) {
+	case string:
+		delete(parent.(map[string]interface{}), k)
+		return data
+	default:
+		array := parent.([]interface{})
+		remaining := append(append([]interface{}{}, array[:k.(int)]...), array[k.(int)+1:]...)
+		return setAt(data, parentLoc, remaining)
+	}
+}
+
+// removeLocations removes several values at once. Later elements go first so the indices of
+// earlier ones stay valid, and members go before the containers that hold them.
+func removeLocations(data interface{}, locations []location) interface{} {
+	sort.Slice(locations, func(i, j int) bool {
+		return compareValues([]interface{}(locations[i]), []interface{}(locations[j])) > 0
+	})
+	for _, loc := range locations {
+		data = removeAt(data, loc)
+	}
+	return data
+}
+
+// copyValue returns a deep copy of a decoded value
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			object[key] = copyValue(child)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, child := range v {
+			array[i] = copyValue(child)
+		}
+		return array
+	default:
+		return v
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/spf13/cobra"
+)
+
+var mapExpression string
+
+// mapLanguage is the expression language of map: gval's operators, with value and key
+// bound to each match and a few functions for text and numbers
+var mapLanguage = gval.Full(
+	gval.Function("lower", strings.ToLower),
+	gval.Function("upper", strings.ToUpper),
+	gval.Function("trim", strings.TrimSpace),
+	gval.Function("replace", func(s, old, new string) string { return strings.ReplaceAll(s, old, new) }),
+	gval.Function("round", func(x float64, places ...float64) float64 {
+		scale := 1.0
+		if len(places) > 0 {
+			scale = math.Pow(10, places[0])
+		}
+		return math.Round(x*scale) / scale
+	}),
+	gval.Function("floor", math.Floor),
+	gval.Function("ceil", math.Ceil),
+	gval.Function("abs", math.Abs),
+	gval.Function("len", func(value interface{}) float64 { return float64(valueLength(value)) }),
+	gval.Function("string", func(value interface{}) string { return listingText(value) }),
+)
+
+// mapCmd represents the map command
+var mapCmd = &cobra.Command{
+	Use:   "map <jsonpath>",
+	Short: "Replace the values a path matches with the result of an expression",
+	Long: `Evaluate --expr for every value a path matches and replace the value with the result,
+printing the modified document or rewriting the file with -i. In the expression, value is
+the matched value and key its member name or index. Expressions use arithmetic, comparison
+and logical operators, string concatenation with +, value.member and value[index] access,
+the conditional a ? b : c, and the functions lower, upper, trim, replace(s, old, new),
+round(x[, places]), floor, ceil, abs, len (as for count) and string:
+
+  $ mycli map -f prices.json '$.items[*].price' --expr 'round(value * 1.2, 2)'
+  $ mycli map -f users.json '$[*].email' --expr 'lower(trim(value))' -i`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPath := args[0]
+		expression, err := mapLanguage.NewEvaluable(mapExpression)
+		if err != nil {
+			return fmt.Errorf("Error parsing expression: %v", err)
+		}
+
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		locations, err := locateJSONPath(jsonData, jsonPath)
+		if err != nil {
+			return jsonPathError(jsonPath, err)
+		}
+
+		// Every result is computed from the document as it was read
+		results := make([]interface{}, len(locations))
+		for i, loc := range locations {
+			value, _ := getAt(jsonData, loc)
+			var key interface{} = nil
+			if len(loc) > 0 {
+				key = loc[len(loc)-1]
+			}
+			result, err := expression(context.Background(), map[string]interface{}{"value": value, "key": key})
+			if err != nil {
+				return fmt.Errorf("Error evaluating expression at %s: %v", loc, err)
+			}
+			if results[i], err = jsonValue(result); err != nil {
+				return fmt.Errorf("Error evaluating expression at %s: %v", loc, err)
+			}
+		}
+		for i, loc := range locations {
+			jsonData = setAt(jsonData, loc, results[i])
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mapCmd)
+
+	addEditFlags(mapCmd)
+	mapCmd.Flags().StringVar(&mapExpression, "expr", "", "Expression computing each new value from value and key")
+	mapCmd.MarkFlagRequired("expr")
+}
+
+// jsonValue converts an expression result, which may use any Go type, into the generic tree
+func jsonValue(result interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("result cannot be represented in JSON: %v", err)
+	}
+	var value interface{}
+	err = json.Unmarshal(encoded, &value)
+	return value, err
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeOptions control how override documents are merged into a base
+type mergeOptions struct {
+	// arrays is replace, append or merge-by-key
+	arrays string
+	// key names the member that identifies array items for merge-by-key
+	key string
+	// nulls is keep, delete or skip
+	nulls string
+}
+
+var (
+	mergeArrays string
+	mergeKey    string
+	mergeNulls  string
+)
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <base> <override>...",
+	Short: "Deep merge documents, later ones overriding earlier ones",
+	Long: `Deep merge documents, as for layered configuration: objects merge member by member,
+and any other value in a later document replaces the earlier one. --arrays chooses whether
+arrays are replaced, appended, or merged item by item on the member named by --key, and
+--nulls whether a null overrides a value, deletes it, or is skipped:
+
+  $ mycli merge base.json production.json local.json
+  $ mycli merge base.json override.json --arrays merge-by-key --key name --nulls delete`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options := mergeOptions{arrays: mergeArrays, key: mergeKey, nulls: mergeNulls}
+		if err := options.validate(); err != nil {
+			return err
+		}
+
+		merged, err := loadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("%s: %v", args[0], err)
+		}
+		for _, path := range args[1:] {
+			override, err := loadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+			merged = mergeValues(merged, override, options)
+		}
+		return prettyPrintJSON(merged)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	addResultFlags(mergeCmd)
+
+	mergeCmd.Flags().StringVar(&mergeArrays, "arrays", "replace", "How arrays merge: replace, append or merge-by-key")
+	mergeCmd.RegisterFlagCompletionFunc("arrays", cobra.FixedCompletions([]string{"replace", "append", "merge-by-key"}, cobra.ShellCompDirectiveNoFileComp))
+	mergeCmd.Flags().StringVar(&mergeKey, "key", "id", "Member identifying array items for --arrays merge-by-key")
+	mergeCmd.Flags().StringVar(&mergeNulls, "nulls", "keep", "What an overriding null does: keep it, delete the member, or skip it")
+	mergeCmd.RegisterFlagCompletionFunc("nulls", cobra.FixedCompletions([]string{"keep", "delete", "skip"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// validate rejects unknown strategies
+func (options mergeOptions) validate() error {
+	switch options.arrays {
+	case "replace", "append", "merge-by-key":
+	default:
+		return fmt.Errorf("unsupported array strategy: %s (use replace, append or merge-by-key)", options.arrays)
+	}
+	switch options.nulls {
+	case "keep", "delete", "skip":
+	default:
+		return fmt.Errorf("unsupported null handling: %s (use keep, delete or skip)", options.nulls)
+	}
+	return nil
+}
+
+// mergeValues merges override into base, modifying base and returning the result. An object
+// override merged into anything but an object starts from an empty object, so its nulls are
+// handled the same way wherever they appear.
+func mergeValues(base interface{}, override interface{}, options mergeOptions) interface{} {
+	switch o := override.(type) {
+	case map[string]interface{}:
+		object, ok := base.(map[string]interface{})
+		if !ok {
+			object = map[string]interface{}{}
+		}
+		for key, value := range o {
+			if value == nil && options.nulls != "keep" {
+				if options.nulls == "delete" {
+					delete(object, key)
+				}
+				continue
+			}
+			object[key] = mergeValues(object[key], value, options)
+		}
+		return object
+	case []interface{}:
+		array, ok := base.([]interface{})
+		if !ok || options.arrays == "replace" {
+			return copyValue(o)
+		}
+		if options.arrays == "append" {
+			return append(array, copyValue(o).([]interface{})...)
+		}
+		return mergeByKey(array, o, options)
+	default:
+		return o
+	}
+}
+
+// mergeByKey merges each override item into the base item with the same --key value,
+// appending items that match none or have no key
+func mergeByKey(base []interface{}, override []interface{}, options mergeOptions) []interface{} {
+	positions := map[string]int{}
+	for i, item := range base {
+		if id, ok := mergeItemKey(item, options.key); ok {
+			positions[id] = i
+		}
+	}
+
+	for _, item := range override {
+		id, ok := mergeItemKey(item, options.key)
+		if i, found := positions[id]; ok && found {
+			base[i] = mergeValues(base[i], item, options)
+			continue
+		}
+		if ok {
+			positions[id] = len(base)
+		}
+		base = append(base, copyValue(item))
+	}
+	return base
+}
+
+// mergeItemKey returns the identifying member of an array item, encoded so values of
+// different types stay distinct
+func mergeItemKey(item interface{}, key string) (string, bool) {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	id, ok := object[key]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%T:%v", id, id), true
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// absent stands for a member or element missing from one side of a three-way merge
+var absent = &struct{}{}
+
+// mergeConflict is a path changed differently on both sides of a three-way merge
+type mergeConflict struct {
+	path               location
+	base, ours, theirs interface{}
+}
+
+// merge3Cmd represents the merge3 command
+var merge3Cmd = &cobra.Command{
+	Use:   "merge3 <base> <ours> <theirs>",
+	Short: "Merge two edits of a document against their common ancestor",
+	Long: `Merge the changes ours and theirs each made to base. Objects merge member by member,
+and arrays of the same length element by element; a value changed on only one side takes
+that change. Where both sides changed a value differently, ours is kept and the conflict
+is reported on stderr with its path, and the command exits with status 1. With -i the
+result replaces ours, so mycli works as a git merge driver:
+
+  $ mycli merge3 base.json ours.json theirs.json
+
+  # .gitattributes:  *.json merge=mycli
+  $ git config merge.mycli.driver 'mycli merge3 -i %O %A %B'`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateInPlace(args[1]); err != nil {
+			return err
+		}
+
+		documents := make([]interface{}, len(args))
+		for i, path := range args {
+			document, err := loadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+			documents[i] = document
+		}
+
+		conflicts := []mergeConflict{}
+		merged := merge3Values(documents[0], documents[1], documents[2], location{}, &conflicts)
+
+		var err error
+		if inPlace {
+			err = writeDocument(args[1], merged)
+		} else {
+			err = prettyPrintJSON(merged)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(conflicts) == 0 {
+			return nil
+		}
+		for _, conflict := range conflicts {
+			fmt.Fprintf(os.Stderr, "conflict at %s: ours %s, theirs %s, base %s\n", conflict.path,
+				mergeSideText(conflict.ours), mergeSideText(conflict.theirs), mergeSideText(conflict.base))
+		}
+		return errFalseResult
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(merge3Cmd)
+	addResultFlags(merge3Cmd)
+
+	addInPlaceFlags(merge3Cmd)
+}
+
+// merge3Values merges the changes ours and theirs made to base at path, any of which may
+// be absent. It returns the merged value, or absent when the merge removes it.
+func merge3Values(base, ours, theirs interface{}, path location, conflicts *[]mergeConflict) interface{} {
+	switch {
+	case mergeSidesEqual(ours, theirs), mergeSidesEqual(base, theirs):
+		return ours
+	case mergeSidesEqual(base, ours):
+		return theirs
+	}
+
+	switch o := ours.(type) {
+	case map[string]interface{}:
+		if t, ok := theirs.(map[string]interface{}); ok {
+			// Members added on both sides merge as if they had started out empty
+			b, ok := base.(map[string]interface{})
+			if !ok {
+				b = map[string]interface{}{}
+			}
+			return merge3Objects(b, o, t, path, conflicts)
+		}
+	case []interface{}:
+		t, ok := theirs.([]interface{})
+		b, isArray := base.([]interface{})
+		if ok && isArray && len(b) == len(o) && len(o) == len(t) {
+			merged := make([]interface{}, len(o))
+			for i := range o {
+				merged[i] = merge3Values(b[i], o[i], t[i], appendLocation(path, i), conflicts)
+			}
+			return merged
+		}
+	}
+
+	*conflicts = append(*conflicts, mergeConflict{path: path, base: base, ours: ours, theirs: theirs})
+	return ours
+}
+
+// merge3Objects merges every member present on any side, in key order so conflicts are
+// reported in a stable order
+func merge3Objects(base, ours, theirs map[string]interface{}, path location, conflicts *[]mergeConflict) map[string]interface{} {
+	keys := map[string]interface{}{}
+	for _, object := range []map[string]interface{}{base, ours, theirs} {
+		for key := range object {
+			keys[key] = nil
+		}
+	}
+
+	merged := map[string]interface{}{}
+	for _, key := range sortedKeys(keys) {
+		value := merge3Values(mergeMember(base, key), mergeMember(ours, key), mergeMember(theirs, key), appendLocation(path, key), conflicts)
+		if value != absent {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// mergeMember returns an object's member, or absent when it has none
+func mergeMember(object map[string]interface{}, key string) interface{} {
+	if value, ok := object[key]; ok {
+		return value
+	}
+	return absent
+}
+
+// mergeSidesEqual compares two sides of a merge, either of which may be absent
+func mergeSidesEqual(a, b interface{}) bool {
+	if a == absent || b == absent {
+		return a == b
+	}
+	return equalValues(a, b)
+}
+
+// mergeSideText describes one side of a conflict
+func mergeSideText(value interface{}) string {
+	if value == absent {
+		return "(deleted)"
+	}
+	return diffValueText(value)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	minifyFile      string
+	minifyDropNulls bool
+)
+
+// minifyCmd represents the minify command
+var minifyCmd = &cobra.Command{
+	Use:   "minify",
+	Short: "Strip insignificant whitespace from a JSON document",
+	Long: `Strip insignificant whitespace from a JSON document, keeping key order and
+number formatting as they are:
+
+  $ mycli minify -f fixture.json -i --drop-nulls`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateInPlace(minifyFile); err != nil {
+			return err
+		}
+
+		input := io.Reader(os.Stdin)
+		if minifyFile != "" {
+			file, err := os.Open(minifyFile)
+			if err != nil {
+				return fmt.Errorf("Error reading file: %v", err)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return fmt.Errorf("Error reading input: %v", err)
+		}
+		minified, err := minifyJSON(data, minifyDropNulls)
+		if err != nil {
+			return err
+		}
+		minified = append(minified, '\n')
+
+		if inPlace {
+			return writeInPlace(minifyFile, minified)
+		}
+		_, err = output.Write(minified)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(minifyCmd)
+
+	minifyCmd.Flags().StringVarP(&minifyFile, "file", "f", "", "JSON file to minify (defaults to stdin)")
+	addInPlaceFlags(minifyCmd)
+	minifyCmd.Flags().BoolVar(&minifyDropNulls, "drop-nulls", false, "Also remove object fields whose value is null")
+}
+
+// minifyJSON removes whitespace from a document, optionally dropping null object fields
+func minifyJSON(data []byte, dropNulls bool) ([]byte, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if !dropNulls {
+		if err := json.Compact(&buf, data); err != nil {
+			return nil, fmt.Errorf("Error parsing JSON: %v", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	// Copy token by token so key order and number text survive
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err == nil {
+		err = writeMinifiedValue(&buf, decoder, token)
+	}
+	if err == nil {
+		if _, trailing := decoder.Token(); trailing != io.EOF {
+			err = errors.New("unexpected data after top-level value")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing JSON: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeMinifiedValue copies the value starting at token, skipping object members that are null
+func writeMinifiedValue(buf *bytes.Buffer, decoder *json.Decoder, token json.Token) error {
+	switch t := token.(type) {
+	case json.Delim:
+		closing := byte(']')
+		if t == '{' {
+			closing = '}'
+		}
+		buf.WriteByte(byte(t))
+		first := true
+		for decoder.More() {
+			var key string
+			if t == '{' {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key = keyToken.(string)
+			}
+			value, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if t == '{' && value == nil {
+				continue
+			}
+
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			if t == '{' {
+				writeMinifiedString(buf, key)
+				buf.WriteByte(':')
+			}
+			if err := writeMinifiedValue(buf, decoder, value); err != nil {
+				return err
+			}
+		}
+		// Consume the closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		buf.WriteByte(closing)
+	case string:
+		writeMinifiedString(buf, t)
+	case json.Number:
+		buf.WriteString(t.String())
+	case bool:
+		buf.WriteString(fmt.Sprint(t))
+	case nil:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+// writeMinifiedString writes a JSON string without escaping HTML characters
+func writeMinifiedString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	// Encode always appends a newline
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// newRelocateCommand builds the move and copy commands, which differ only in whether the
+// source is removed
+func newRelocateCommand(name string, verb string, short string, remove bool) *cobra.Command {
+	command := &cobra.Command{
+		Use:   name + " <from-jsonpath> <to-jsonpath>",
+		Short: short,
+		Long: short + ` and print the modified document, or rewrite the file
+with -i. As with JSON Patch, the source must exist, missing objects along the target path
+are created, and an array index in the target inserts before the element already there:
+
+  $ mycli ` + name + ` -f config.json '$.server.port' '$.listeners[0].port'`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: jsonPathCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonData, err := loadEditDocument()
+			if err != nil {
+				return err
+			}
+			if jsonData, err = relocate(jsonData, args[0], args[1], remove); err != nil {
+				return fmt.Errorf("Error %s: %v", verb, err)
+			}
+			return saveEditedDocument(jsonData)
+		},
+	}
+	addEditFlags(command)
+	return command
+}
+
+func init() {
+	rootCmd.AddCommand(newRelocateCommand("move", "moving", "Move the value at one path to another", true))
+	rootCmd.AddCommand(newRelocateCommand("copy", "copying", "Copy the value at one path to another", false))
+}
+
+// relocate adds the value found at one path at another, removing it from the first when moving
+func relocate(jsonData interface{}, fromPath string, toPath string, remove bool) (interface{}, error) {
+	locations, err := locateJSONPath(jsonData, fromPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) != 1 {
+		return nil, fmt.Errorf("%s must match exactly one value, matched %d", fromPath, len(locations))
+	}
+	from := locations[0]
+
+	to, ok := singularLocation(toPath)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a path of plain keys and indices", toPath)
+	}
+
+	value, _ := getAt(jsonData, from)
+	if remove {
+		if isPrefixLocation(from, to) {
+			if len(from) == len(to) {
+				return jsonData, nil
+			}
+			return nil, fmt.Errorf("cannot move %s into itself", from)
+		}
+		if len(from) == 0 {
+			return nil, fmt.Errorf("cannot move the document root")
+		}
+		jsonData = removeAt(jsonData, from)
+	} else {
+		value = copyValue(value)
+	}
+	return createAt(jsonData, to, value, true)
+}
+
+// isPrefixLocation reports whether prefix is the start, or all, of other
+func isPrefixLocation(prefix location, other location) bool {
+	if len(prefix) > len(other) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+var (
+	natsURL     string
+	natsSubject string
+)
+
+func init() {
+	readCmd.Flags().StringVar(&natsURL, "nats", "", "NATS server URL to subscribe to instead of reading a file")
+	readCmd.Flags().StringVar(&natsSubject, "subject", "", "NATS subject to subscribe to (wildcards allowed)")
+}
+
+// readNATS subscribes to a NATS subject and passes each JSON message to the handler
+func readNATS(url string, subject string, handle documentHandler) error {
+	if subject == "" {
+		return fmt.Errorf("Please specify a subject using the --subject flag.")
+	}
+
+	nc, err := nats.Connect(url)
+	if err != nil {
+		return fmt.Errorf("Error connecting to NATS: %v", err)
+	}
+	defer nc.Close()
+
+	sub, err := nc.SubscribeSync(subject)
+	if err != nil {
+		return fmt.Errorf("Error subscribing to %s: %v", subject, err)
+	}
+	defer sub.Unsubscribe()
+
+	ctx, cancel := streamContext()
+	defer cancel()
+
+	for received := 0; !streamLimitReached(received); received++ {
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Interrupted or --duration elapsed
+				return nil
+			}
+			return fmt.Errorf("Error receiving message: %v", err)
+		}
+		handleStreamMessage(msg.Data, handle)
+	}
+	return nil
+}
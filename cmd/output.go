@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	outputFormat string
+	rawOutput    bool
+	compactJSON  bool
+	jsonLines    bool
+	indentWidth  string
+	sortKeys     bool
+	outputFile   string
+)
+
+// output receives printed results; it buffers them when --output-file is set
+var output io.Writer = os.Stdout
+
+// outputBuffer holds results for --output-file or the pager, and clipboardBuffer a copy for --copy
+var (
+	outputBuffer    *bytes.Buffer
+	clipboardBuffer *bytes.Buffer
+)
+
+// outputFormatter renders a result in a single output format
+type outputFormatter func(data interface{}) ([]byte, error)
+
+// outputFormatters maps each supported --output value to its formatter
+var outputFormatters = map[string]outputFormatter{
+	"json":      formatJSON,
+	"canonical": formatCanonical,
+	"yaml":      formatYAML,
+	"csv":       formatCSV,
+	"tsv":       formatTSV,
+	"table":     formatTable,
+	"markdown":  formatMarkdown,
+	"html":      formatHTML,
+	"gron":      formatGron,
+	"toml":      formatTOML,
+	"env":       formatEnv,
+	"shell":     formatShell,
+	"dot":       formatDOT,
+	"tree":      formatTree,
+	"msgpack":   formatMsgpack,
+	"cbor":      formatCBOR,
+	"proto":     formatProto,
+	"xlsx":      formatXLSX,
+	"parquet":   formatParquet,
+}
+
+// binaryOutputFormats are written byte for byte, without a trailing newline
+var binaryOutputFormats = map[string]bool{
+	"canonical": true,
+	"msgpack":   true,
+	"cbor":      true,
+	"proto":     true,
+	"xlsx":      true,
+	"parquet":   true,
+}
+
+// flagCompletion completes the value of a flag
+type flagCompletion func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// resultFlags control how formatted results are printed. They are only attached to the
+// commands that print such results, listed in resultCommands, so the others stay uncluttered.
+var (
+	resultFlags           = pflag.NewFlagSet("results", pflag.ContinueOnError)
+	resultFlagCompletions = map[string]flagCompletion{}
+	resultCommands        []*cobra.Command
+)
+
+func init() {
+	resultFlags.StringVarP(&outputFormat, "output", "o", "json", "Output format for results")
+	resultFlagCompletions["output"] = outputFormatCompletion
+	resultFlags.BoolVarP(&rawOutput, "raw-output", "r", false, "Print string results without quotes or escaping")
+	resultFlags.BoolVarP(&compactJSON, "compact", "c", false, "Print JSON results minified on a single line")
+	resultFlags.StringVar(&indentWidth, "indent", "2", "Indentation for JSON and YAML output: a number of spaces or 'tab'")
+	resultFlags.BoolVar(&sortKeys, "sort-keys", false, "Sort object keys in msgpack and CBOR output as well; text formats always sort them")
+	resultFlags.BoolVar(&jsonLines, "jsonl", false, "Print each match of a multi-match result as JSON on its own line")
+
+	// Where output goes matters to every command that prints anything
+	rootCmd.PersistentFlags().StringVarP(&outputFile, "output-file", "O", "", "Write results to this file atomically instead of stdout")
+}
+
+// addResultFlags marks a command as printing formatted results, so it takes the result flags
+func addResultFlags(cmd *cobra.Command) {
+	resultCommands = append(resultCommands, cmd)
+}
+
+// attachResultFlags adds the result flags to the commands that print formatted results. It runs
+// from Execute because the flags are registered by the init functions of several files.
+func attachResultFlags() {
+	for i, cmd := range resultCommands {
+		cmd.Flags().AddFlagSet(resultFlags)
+		// The flags are shared, and completions are registered per flag rather than per command
+		if i == 0 {
+			for name, complete := range resultFlagCompletions {
+				cmd.RegisterFlagCompletionFunc(name, complete)
+			}
+		}
+	}
+}
+
+// setupOutput buffers results in memory when they are destined for --output-file, a pager or the clipboard
+func setupOutput() {
+	if outputFile != "" || pagerWanted() {
+		outputBuffer = &bytes.Buffer{}
+		output = outputBuffer
+	}
+	if copyOutput {
+		clipboardBuffer = &bytes.Buffer{}
+		output = io.MultiWriter(output, clipboardBuffer)
+	}
+}
+
+// flushOutput delivers buffered results. The --output-file and clipboard are only updated
+// when the command succeeded, while paged output is always shown.
+func flushOutput(succeeded bool) error {
+	if clipboardBuffer != nil && succeeded {
+		if err := writeClipboard(clipboardBuffer.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if outputBuffer == nil {
+		return nil
+	}
+	if outputFile == "" {
+		return pageOutput(outputBuffer.Bytes())
+	}
+	if !succeeded {
+		return nil
+	}
+	if err := writeFileAtomic(outputFile, outputBuffer.Bytes()); err != nil {
+		return fmt.Errorf("Error writing output file: %v", err)
+	}
+	return nil
+}
+
+// formatOutput renders data in the format selected with --output
+func formatOutput(data interface{}) ([]byte, error) {
+	if s, ok := data.(string); ok && rawOutput {
+		return []byte(s), nil
+	}
+	if templateRequested() {
+		return formatTemplate(data)
+	}
+	if jsonLines {
+		return formatJSONLines(data)
+	}
+
+	formatter, ok := outputFormatters[outputFormat]
+	if !ok {
+		return nil, fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+	return formatter(data)
+}
+
+// formatJSON renders data as indented JSON, or on a single line with --compact
+func formatJSON(data interface{}) ([]byte, error) {
+	return marshalJSON(data, compactJSON)
+}
+
+// formatJSONLines renders each element of an array as compact JSON on its own line
+func formatJSONLines(data interface{}) ([]byte, error) {
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+
+	var buf bytes.Buffer
+	for _, item := range items {
+		line, err := jsonLine(item)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonLine renders one value as a single newline-terminated line, unquoting strings with -r
+func jsonLine(item interface{}) ([]byte, error) {
+	if s, ok := item.(string); ok && rawOutput {
+		return []byte(s + "\n"), nil
+	}
+	line, err := marshalJSON(item, true)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// indentString converts --indent into the string used for one level of indentation
+func indentString() (string, error) {
+	if indentWidth == "tab" {
+		return "\t", nil
+	}
+	width, err := strconv.Atoi(indentWidth)
+	if err != nil || width < 0 || width > 16 {
+		return "", fmt.Errorf("invalid --indent %q: expected 0-16 or 'tab'", indentWidth)
+	}
+	return strings.Repeat(" ", width), nil
+}
+
+// formatYAML renders data as a YAML document, indented per --indent
+func formatYAML(data interface{}) ([]byte, error) {
+	indent, err := indentString()
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(indent, "\t") || indent == "" {
+		// YAML forbids tabs and needs some indentation, so keep the default
+		indent = "    "
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(len(indent))
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatTOML renders an object as a TOML document
+func formatTOML(data interface{}) ([]byte, error) {
+	if _, ok := data.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("TOML output requires an object, got %s", jsonTypeName(data))
+	}
+	return toml.Marshal(integralNumbers(data))
+}
+
+// integralNumbers converts whole-number floats to int64 so formats with an integer type keep them unadorned
+func integralNumbers(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[key] = integralNumbers(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = integralNumbers(value)
+		}
+		return result
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// formatMsgpack encodes data as MessagePack, keeping whole numbers as integers and sorting
+// object keys with --sort-keys
+func formatMsgpack(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetSortMapKeys(sortKeys)
+	if err := encoder.Encode(integralNumbers(data)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatCBOR encodes data as CBOR, keeping whole numbers as integers and sorting object keys
+// with --sort-keys
+func formatCBOR(data interface{}) ([]byte, error) {
+	options := cbor.EncOptions{}
+	if sortKeys {
+		options.Sort = cbor.SortBytewiseLexical
+	}
+	mode, err := options.EncMode()
+	if err != nil {
+		return nil, err
+	}
+	return mode.Marshal(integralNumbers(data))
+}
+
+// formatCSV renders an array of objects as CSV with a header row
+func formatCSV(data interface{}) ([]byte, error) {
+	header, rows, err := tabulate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(header)
+	writer.WriteAll(rows)
+	return buf.Bytes(), writer.Error()
+}
+
+// tsvEscaper escapes the characters that would break tab-separated columns and rows
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// formatTSV renders an array of objects as tab-separated values with a header row.
+// Fields are never quoted; tabs, newlines and backslashes are backslash-escaped instead.
+func formatTSV(data interface{}) ([]byte, error) {
+	header, rows, err := tabulate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if i > 0 {
+				buf.WriteByte('\t')
+			}
+			buf.WriteString(tsvEscaper.Replace(cell))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// outputFormatCompletion suggests the supported output formats
+func outputFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := make([]string, 0, len(outputFormatters))
+	for format := range outputFormatters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats, cobra.ShellCompDirectiveNoFileComp
+}
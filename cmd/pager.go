@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+
+	"golang.org/x/term"
+)
+
+var noPager bool
+
+// defaultPager is used when neither MYCLI_PAGER nor PAGER is set
+const defaultPager = "less"
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Never pipe output through $PAGER")
+}
+
+// pagerWanted reports whether output should be buffered for a possible pager: stdout must
+// be a terminal, and live streams are excluded so their messages appear as they arrive
+func pagerWanted() bool {
+	return !noPager && outputFile == "" && !isStreamingSource() && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// pageOutput writes buffered output to the terminal, through the pager if it is taller than the screen
+func pageOutput(data []byte) error {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || bytes.Count(data, []byte("\n")) < height {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	pager := os.Getenv("MYCLI_PAGER")
+	if pager == "" {
+		pager = os.Getenv("PAGER")
+	}
+	if pager == "" {
+		pager = defaultPager
+	}
+
+	// Run through the shell so the pager command may carry its own arguments
+	command := exec.Command("sh", "-c", pager)
+	command.Stdin = bytes.NewReader(data)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		// Quit if one screen, keep colors, and leave the output on screen, like git
+		command.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	if err := command.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// The pager ran; a non-zero exit such as quitting early is not our error
+			return nil
+		}
+		// The pager could not be started, so fall back to plain output
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// formatParquet writes an array of objects as a Parquet file. Each column's type is
+// inferred from its values: integers, doubles and booleans when every non-null value
+// agrees, otherwise strings. All columns are optional so missing values become nulls.
+func formatParquet(data interface{}) ([]byte, error) {
+	header, records, err := tabulateRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	group := parquet.Group{}
+	for _, column := range header {
+		group[column] = parquet.Optional(inferParquetNode(column, records))
+	}
+	schema := parquet.NewSchema("result", group)
+
+	var buf bytes.Buffer
+	writer := parquet.NewWriter(&buf, schema)
+
+	// The schema orders columns by name, so build each row in that order
+	columns := schema.Columns()
+	rows := make([]parquet.Row, len(records))
+	for r, record := range records {
+		row := make(parquet.Row, len(columns))
+		for c, path := range columns {
+			leaf, _ := schema.Lookup(path...)
+			value := parquetValue(record[path[0]], leaf.Node.Type().Kind())
+			if value.IsNull() {
+				row[c] = value.Level(0, 0, c)
+			} else {
+				row[c] = value.Level(0, 1, c)
+			}
+		}
+		rows[r] = row
+	}
+
+	if _, err := writer.WriteRows(rows); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// inferParquetNode picks the narrowest Parquet type that holds every non-null value of a column
+func inferParquetNode(column string, records []map[string]interface{}) parquet.Node {
+	integers, numbers, booleans, seen := true, true, true, false
+	for _, record := range records {
+		switch v := record[column].(type) {
+		case nil:
+			continue
+		case float64:
+			booleans = false
+			if v != math.Trunc(v) || math.Abs(v) >= 1<<53 {
+				integers = false
+			}
+		case bool:
+			integers, numbers = false, false
+		default:
+			integers, numbers, booleans = false, false, false
+		}
+		seen = true
+	}
+
+	switch {
+	case !seen:
+		return parquet.String()
+	case integers:
+		return parquet.Int(64)
+	case numbers:
+		return parquet.Leaf(parquet.DoubleType)
+	case booleans:
+		return parquet.Leaf(parquet.BooleanType)
+	default:
+		return parquet.String()
+	}
+}
+
+// parquetValue converts a value to the column's physical type
+func parquetValue(value interface{}, kind parquet.Kind) parquet.Value {
+	if value == nil {
+		return parquet.NullValue()
+	}
+	switch kind {
+	case parquet.Int64:
+		return parquet.Int64Value(int64(value.(float64)))
+	case parquet.Double:
+		return parquet.DoubleValue(value.(float64))
+	case parquet.Boolean:
+		return parquet.BooleanValue(value.(bool))
+	default:
+		return parquet.ByteArrayValue([]byte(cellText(value)))
+	}
+}
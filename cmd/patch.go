@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var mergePatch bool
+
+// patchCmd represents the patch command
+var patchCmd = &cobra.Command{
+	Use:   "patch <patch-file>",
+	Short: "Apply a patch document to a document",
+	Long: `Apply a patch to the document given with -f and print the result, or rewrite the file
+with -i. The patch is a JSON Patch (RFC 6902): an array of add, remove, replace, move, copy
+and test operations, applied in order. If any operation fails, including a test, the error
+is reported and nothing is changed. Use - to read the patch from stdin.
+
+With --merge, the patch is a JSON Merge Patch (RFC 7386) instead: objects merge member by
+member, null deletes a member, and any other value replaces the target.
+
+  $ mycli patch -f deployment.json ops.json -i
+  $ mycli patch --merge -f deployment.json replicas.json -i`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if filePath == "" && args[0] == "-" {
+			return fmt.Errorf("Please specify the document to patch with -f when reading the patch from stdin")
+		}
+
+		patch, err := loadPatch(args[0])
+		if err != nil {
+			return err
+		}
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		if mergePatch {
+			return saveEditedDocument(applyMergePatch(jsonData, patch))
+		}
+		if jsonData, err = applyJSONPatch(jsonData, patch); err != nil {
+			return err
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(patchCmd)
+
+	addEditFlags(patchCmd)
+	patchCmd.Flags().BoolVar(&mergePatch, "merge", false, "Apply the patch as a JSON Merge Patch (RFC 7386)")
+}
+
+// loadPatch reads a patch document from a file, or from stdin for -
+func loadPatch(path string) (interface{}, error) {
+	if path != "-" {
+		patch, err := loadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return patch, nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading input: %v", err)
+	}
+	return decodeDocument(parseJSON, data)
+}
+
+// applyMergePatch applies an RFC 7386 merge patch, which is a deep merge in which arrays
+// are replaced and nulls delete members
+func applyMergePatch(target interface{}, patch interface{}) interface{} {
+	return mergeValues(target, patch, mergeOptions{arrays: "replace", nulls: "delete"})
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var pathsShowTypes bool
+
+// pathsCmd represents the paths command
+var pathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "List the JSONPath of every leaf value in a document",
+	Long: `List the JSONPath of every leaf value in a document, one per line, so an unfamiliar
+document can be explored with grep. Empty objects and arrays count as leaves, and so do
+containers at --max-depth:
+
+  $ mycli paths -f deployment.json --types | grep image`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+
+		writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		for _, leaf := range leafLocations(jsonData, truncationLimit(maxDepth)) {
+			if pathsShowTypes {
+				value, _ := getAt(jsonData, leaf)
+				fmt.Fprintf(writer, "%s\t%s\n", leaf, jsonTypeName(value))
+			} else {
+				fmt.Fprintln(writer, leaf)
+			}
+		}
+		return writer.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pathsCmd)
+
+	pathsCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to list the paths of (defaults to JSON on stdin)")
+	pathsCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	pathsCmd.Flags().BoolVar(&pathsShowTypes, "types", false, "Show the JSON type of each value")
+	pathsCmd.Flags().IntVar(&maxDepth, "max-depth", 0, "List containers deeper than this many levels as single paths (0 for no limit)")
+	pathsCmd.Flags().BoolVar(&noTruncate, "no-truncate", false, "Disable --max-depth")
+}
+
+// leafLocations returns the location of every scalar and empty container in document order,
+// stopping at containers depthLimit levels down when it is positive
+func leafLocations(data interface{}, depthLimit int) []location {
+	leaves := []location{}
+	var walk func(node interface{}, loc location)
+	walk = func(node interface{}, loc location) {
+		children := childLocations(node, loc)
+		if len(children) == 0 || (depthLimit > 0 && len(loc) >= depthLimit) {
+			leaves = append(leaves, loc)
+			return
+		}
+		for _, child := range children {
+			value, _ := getAt(node, child[len(loc):])
+			walk(value, child)
+		}
+	}
+	walk(data, location{})
+	return leaves
+}
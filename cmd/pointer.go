@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var jsonPointer string
+
+// pointerEscaper and pointerUnescaper convert between keys and RFC 6901 reference tokens
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+func init() {
+	readCmd.Flags().StringVar(&jsonPointer, "pointer", "", "JSON Pointer (RFC 6901) to look up instead of a JSONPath, e.g. /store/book/0/title")
+	readCmd.RegisterFlagCompletionFunc("pointer", jsonPointerCompletion)
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer must be empty or start with '/'")
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+// pointerLocation resolves a JSON Pointer against a document to the location it refers to
+func pointerLocation(data interface{}, pointer string) (location, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := location{}
+	node := data
+	for i, token := range tokens {
+		at := "/" + strings.Join(escapePointerTokens(tokens[:i]), "/")
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("no key %q at %s", token, at)
+			}
+			loc = append(loc, token)
+			node = child
+		case []interface{}:
+			index, err := pointerIndex(token)
+			if err != nil {
+				return nil, fmt.Errorf("%v at %s", err, at)
+			}
+			if index >= len(v) {
+				return nil, fmt.Errorf("index %d out of bounds at %s", index, at)
+			}
+			loc = append(loc, index)
+			node = v[index]
+		default:
+			return nil, fmt.Errorf("cannot descend into %s at %s", jsonTypeName(node), at)
+		}
+	}
+	return loc, nil
+}
+
+// pointerIndex parses an array index token, which must be a decimal without leading zeros
+func pointerIndex(token string) (int, error) {
+	if token == "-" {
+		return 0, fmt.Errorf("'-' refers past the end of the array")
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+// pointer renders a location as a JSON Pointer
+func (loc location) pointer() string {
+	var b strings.Builder
+	for _, key := range loc {
+		b.WriteString("/")
+		switch k := key.(type) {
+		case int:
+			b.WriteString(strconv.Itoa(k))
+		case string:
+			b.WriteString(pointerEscaper.Replace(k))
+		}
+	}
+	return b.String()
+}
+
+// escapePointerTokens escapes '~' and '/' in each token
+func escapePointerTokens(tokens []string) []string {
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = pointerEscaper.Replace(token)
+	}
+	return escaped
+}
+
+// resolvePointer returns the value a JSON Pointer refers to
+func resolvePointer(data interface{}, pointer string) (interface{}, error) {
+	loc, err := pointerLocation(data, pointer)
+	if err != nil {
+		return nil, err
+	}
+	value, _ := getAt(data, loc)
+	return value, nil
+}
+
+// jsonPointerCompletion suggests the children of the node named by everything before the last '/'
+func jsonPointerCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !strings.HasPrefix(toComplete, "/") {
+		return []string{"/"}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	jsonData, ok := completionDocument()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cut := strings.LastIndex(toComplete, "/")
+	parent, partial := toComplete[:cut], toComplete[cut+1:]
+	node, err := resolvePointer(jsonData, parent)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var tokens []string
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			tokens = append(tokens, pointerEscaper.Replace(key))
+		}
+		sort.Strings(tokens)
+	case []interface{}:
+		for i := range v {
+			tokens = append(tokens, strconv.Itoa(i))
+		}
+	}
+
+	suggestions := []string{}
+	for _, token := range tokens {
+		if strings.HasPrefix(token, partial) {
+			suggestions = append(suggestions, parent+"/"+token)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
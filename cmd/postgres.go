@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+var postgresDSN string
+
+func init() {
+	readCmd.Flags().StringVar(&postgresDSN, "pg", "", "Postgres connection string to run the --sql statement against")
+}
+
+// readPostgres runs the --sql statement against Postgres and passes each row to the handler
+func readPostgres(dsn string, query string, handle documentHandler) error {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return fmt.Errorf("Error opening Postgres connection: %v", err)
+	}
+	defer db.Close()
+
+	return readSQLRows(db, query, handle)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var (
+	protoDescriptor string
+	protoMessage    string
+)
+
+func init() {
+	resultFlags.StringVar(&protoDescriptor, "descriptor", "", "FileDescriptorSet (protoc --descriptor_set_out --include_imports) for proto output")
+	resultFlags.StringVar(&protoMessage, "message", "", "Fully-qualified message name for proto output, e.g. pkg.v1.Event")
+}
+
+// formatProto validates a result against --message and serializes it as binary protobuf
+func formatProto(data interface{}) ([]byte, error) {
+	descriptor, err := findMessageDescriptor(protoDescriptor, protoMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	message := dynamicpb.NewMessage(descriptor)
+	if err := protojson.Unmarshal(encoded, message); err != nil {
+		return nil, fmt.Errorf("result does not match %s: %v", protoMessage, err)
+	}
+	return proto.Marshal(message)
+}
+
+// findMessageDescriptor loads a descriptor set and looks up a message type in it
+func findMessageDescriptor(path string, name string) (protoreflect.MessageDescriptor, error) {
+	if path == "" || name == "" {
+		return nil, fmt.Errorf("proto output requires --descriptor and --message")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading descriptor: %v", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("Error parsing descriptor: %v", err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing descriptor: %v", err)
+	}
+
+	found, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found in %s", name, path)
+	}
+	descriptor, ok := found.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message type", name)
+	}
+	return descriptor, nil
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	queryExpressions []string
+	queryFile        string
+)
+
+// expressionLabel matches the optional "label=" prefix of an -e expression
+var expressionLabel = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_-]*)=`)
+
+// documentQuery evaluates a compiled query against a single JSON document
+type documentQuery func(jsonData interface{}) (interface{}, error)
+
+func init() {
+	readCmd.Flags().StringArrayVarP(&queryExpressions, "expression", "e", nil, "Query to run, optionally as label=query; repeat to print several labeled results from one read")
+	readCmd.RegisterFlagCompletionFunc("expression", jsonPathCompletion)
+	readCmd.Flags().StringVar(&queryFile, "query-file", "", "Read the query expression from a file; lines starting with # are comments")
+}
+
+// readQueryFile loads an expression from --query-file, dropping comment lines. JSONPath
+// lines are joined without separators so long paths can be wrapped.
+func readQueryFile(path string, language string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading query file: %v", err)
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if language == "jsonpath" {
+			line = strings.TrimSpace(line)
+		}
+		lines = append(lines, line)
+	}
+
+	separator := "\n"
+	if language == "jsonpath" {
+		separator = ""
+	}
+	expression := strings.TrimSpace(strings.Join(lines, separator))
+	if expression == "" {
+		return "", fmt.Errorf("query file %s is empty", path)
+	}
+	return expression, nil
+}
+
+// expandQuery resolves a saved query reference and substitutes --arg and --argjson variables,
+// giving the expression that is actually evaluated
+func expandQuery(language string, expression string) (string, error) {
+	if isSavedQueryReference(expression) {
+		saved, err := resolveSavedQuery(expression)
+		if err != nil {
+			return "", err
+		}
+		expression = saved
+	}
+
+	if language == "jq" {
+		// jq binds variables itself; the other languages take them as literals
+		return expression, nil
+	}
+	variables, err := queryVariables()
+	if err != nil {
+		return "", err
+	}
+	return substituteVariables(expression, language, variables)
+}
+
+// compileQuery prepares an expression in the given query language. An empty expression
+// selects the whole document; fallback replaces results that match nothing when hasDefault is set.
+func compileQuery(language string, expression string, fallback interface{}, hasDefault bool) (documentQuery, error) {
+	if expression == "" {
+		return func(jsonData interface{}) (interface{}, error) {
+			queryStats.matches++
+			return jsonData, nil
+		}, nil
+	}
+
+	expression, err := expandQuery(language, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	switch language {
+	case "jsonpath":
+		if stages := splitPipeline(expression); len(stages) > 1 {
+			return pipelineQuery(stages, fallback, hasDefault), nil
+		}
+		// Strip surrounding double quotes if present
+		return jsonPathQuery(strings.Trim(expression, "\""), fallback, hasDefault), nil
+	case "jq":
+		return jqQuery(expression, fallback, hasDefault)
+	case "jmespath":
+		return jmesPathQuery(expression, fallback, hasDefault)
+	default:
+		return nil, fmt.Errorf("unsupported query language: %s", language)
+	}
+}
+
+// jsonPathQuery evaluates a JSONPath with the selected engine, explaining unknown keys
+func jsonPathQuery(jsonPath string, fallback interface{}, hasDefault bool) documentQuery {
+	return func(jsonData interface{}) (interface{}, error) {
+		start := time.Now()
+		result, err := queryJSONPath(jsonData, jsonPath)
+		queryStats.queryTime += time.Since(start)
+		if hasDefault && isNoMatch(jsonPath, result, err) {
+			return fallback, nil
+		}
+		if err != nil {
+			if isMissingPathError(err) {
+				if hint := unknownKeyHint(jsonData, jsonPath); hint != "" {
+					return nil, fmt.Errorf("Error querying JSONPath: %s", hint)
+				}
+			}
+			return nil, jsonPathError(jsonPath, err)
+		}
+		queryStats.matches += countMatches(jsonPath, result)
+		return result, nil
+	}
+}
+
+// jqQuery runs a jq program; a single output is shown as is, several as an array
+func jqQuery(program string, fallback interface{}, hasDefault bool) (documentQuery, error) {
+	code, values, err := compileJQ(program)
+	if err != nil {
+		return nil, err
+	}
+	return func(jsonData interface{}) (interface{}, error) {
+		start := time.Now()
+		results, err := runJQ(code, values, jsonData)
+		queryStats.queryTime += time.Since(start)
+		if err != nil {
+			return nil, err
+		}
+		queryStats.matches += len(results)
+		switch {
+		case len(results) == 0 && hasDefault:
+			return fallback, nil
+		case len(results) == 1:
+			return results[0], nil
+		default:
+			return results, nil
+		}
+	}, nil
+}
+
+// jmesPathQuery evaluates a JMESPath expression
+func jmesPathQuery(expression string, fallback interface{}, hasDefault bool) (documentQuery, error) {
+	code, err := compileJMESPath(expression)
+	if err != nil {
+		return nil, err
+	}
+	return func(jsonData interface{}) (interface{}, error) {
+		start := time.Now()
+		result, err := code.Search(jsonData)
+		queryStats.queryTime += time.Since(start)
+		if err != nil {
+			return nil, fmt.Errorf("Error evaluating JMESPath: %v", err)
+		}
+		// JMESPath yields null for anything that does not exist
+		if result == nil {
+			if hasDefault {
+				return fallback, nil
+			}
+			return nil, nil
+		}
+		queryStats.matches++
+		return result, nil
+	}, nil
+}
+
+// pointerQuery resolves a JSON Pointer
+func pointerQuery(pointer string, fallback interface{}, hasDefault bool) documentQuery {
+	return func(jsonData interface{}) (interface{}, error) {
+		start := time.Now()
+		result, err := resolvePointer(jsonData, pointer)
+		queryStats.queryTime += time.Since(start)
+		if err != nil {
+			if hasDefault {
+				return fallback, nil
+			}
+			return nil, fmt.Errorf("Error resolving JSON Pointer: %v", err)
+		}
+		queryStats.matches++
+		return result, nil
+	}
+}
+
+// labeledQuery compiles several -e expressions into one query whose result maps each
+// label, or the expression itself when unlabeled, to that expression's result
+func labeledQuery(expressions []string, fallback interface{}, hasDefault bool) (documentQuery, error) {
+	labels := make([]string, len(expressions))
+	queries := make([]documentQuery, len(expressions))
+	for i, expression := range expressions {
+		labels[i] = expression
+		if match := expressionLabel.FindStringSubmatch(expression); match != nil {
+			labels[i] = match[1]
+			expression = expression[len(match[0]):]
+		}
+		query, err := compileQuery(queryLanguage, expression, fallback, hasDefault)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", labels[i], err)
+		}
+		queries[i] = query
+	}
+
+	return func(jsonData interface{}) (interface{}, error) {
+		results := make(map[string]interface{}, len(queries))
+		for i, query := range queries {
+			result, err := query(jsonData)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", labels[i], err)
+			}
+			results[labels[i]] = result
+		}
+		return results, nil
+	}, nil
+}
+
+// splitPipeline splits "$.a[*] | $.b" at each '|' outside quotes, brackets and parentheses,
+// removing quotes wrapped around whole stages
+func splitPipeline(expression string) []string {
+	stages := []string{}
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == '|' && depth == 0:
+			stages = append(stages, expression[start:i])
+			start = i + 1
+		}
+	}
+	stages = append(stages, expression[start:])
+
+	for i, stage := range stages {
+		stage = strings.TrimSpace(stage)
+		if isQuoted(stage) {
+			stage = stage[1 : len(stage)-1]
+		}
+		stages[i] = stage
+	}
+	return stages
+}
+
+// pipelineQuery feeds each match of one JSONPath stage into the next. The result is an
+// array once any stage can match several values; matches a later stage misses are dropped.
+func pipelineQuery(stages []string, fallback interface{}, hasDefault bool) documentQuery {
+	return func(jsonData interface{}) (interface{}, error) {
+		start := time.Now()
+		defer func() { queryStats.queryTime += time.Since(start) }()
+
+		values := []interface{}{jsonData}
+		multi := false
+		for _, stage := range stages {
+			next := []interface{}{}
+			for _, value := range values {
+				result, err := queryJSONPath(value, stage)
+				if isNoMatch(stage, result, err) {
+					if !multi && !isMultiMatchPath(stage) && !hasDefault {
+						if hint := unknownKeyHint(value, stage); hint != "" {
+							return nil, fmt.Errorf("Error querying JSONPath: %s", hint)
+						}
+					}
+					continue
+				}
+				if err != nil {
+					return nil, jsonPathError(stage, err)
+				}
+				if items, ok := result.([]interface{}); ok && isMultiMatchPath(stage) {
+					next = append(next, items...)
+				} else {
+					next = append(next, result)
+				}
+			}
+			values = next
+			multi = multi || isMultiMatchPath(stage)
+		}
+
+		queryStats.matches += len(values)
+		switch {
+		case multi:
+			return values, nil
+		case len(values) == 1:
+			return values[0], nil
+		case hasDefault:
+			return fallback, nil
+		default:
+			return nil, fmt.Errorf("Error querying JSONPath: %v", errNoMatch)
+		}
+	}
+}
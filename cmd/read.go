@@ -2,64 +2,180 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
 
-	"github.com/PaesslerAG/jsonpath"
 	"github.com/spf13/cobra"
 )
 
-var filePath string
+var (
+	filePath     string
+	defaultValue string
+)
+
+// errNoSource is reported when no input source flag was given
+var errNoSource = errors.New("Please specify a file using the -f or --file flag.")
+
+// documentHandler receives each JSON document produced by an input source
+type documentHandler func(jsonData interface{}) error
 
 // readCmd represents the read command
 var readCmd = &cobra.Command{
 	Use:   "read",
 	Short: "Read a JSON file and query it using a JSONPath expression",
-	Args:  cobra.MaximumNArgs(1), // Accept at most one argument
-	Run: func(cmd *cobra.Command, args []string) {
-		if filePath == "" {
-			fmt.Println("Please specify a file using the -f or --file flag.")
-			return
+	Long: `Read a JSON file and query it using a JSONPath expression.
+
+Paths are evaluated by the engine selected with --engine, except in these cases, which use
+mycli's own path locator:
+
+  - with the paessler engine, paths with slices ($.a[1:3]), negative indices ($.a[-1]),
+    unions ($.a[0,2] or $['a','b']) or single-quoted keys, which paessler cannot evaluate
+  - --ignore-case, since the engines match keys exactly
+  - --jsonl with the rfc9535 engine or either case above, so matches stream as they are found
+
+The locator visits object members in sorted key order and evaluates each filter ([?(...)])
+with the selected engine, one candidate at a time. Commands that change a document, type,
+diff --ignore-path and --decode-base64 always find values with the locator, because they
+need to know where each match is.`,
+	Args: cobra.MaximumNArgs(1), // Accept at most one argument
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expressions := queryExpressions
+		if len(args) > 0 {
+			if len(expressions) > 0 {
+				return fmt.Errorf("Use either a query argument or -e, not both")
+			}
+			expressions = args
+		}
+		if queryFile != "" {
+			if len(expressions) > 0 {
+				return fmt.Errorf("Use either a query argument or --query-file, not both")
+			}
+			expression, err := readQueryFile(queryFile, queryLanguage)
+			if err != nil {
+				return err
+			}
+			expressions = []string{expression}
+		}
+
+		// --default replaces results for paths that match nothing
+		var fallback interface{}
+		hasDefault := cmd.Flags().Changed("default")
+		if hasDefault {
+			if err := json.Unmarshal([]byte(defaultValue), &fallback); err != nil {
+				return fmt.Errorf("Error parsing --default as JSON: %v", err)
+			}
 		}
 
-		data, err := os.ReadFile(filePath)
+		// --exists tells matches from misses by whether the fallback comes back
+		if checkExists {
+			if err := validateExists(hasDefault, expressions); err != nil {
+				return err
+			}
+			fallback, hasDefault = noMatch{}, true
+		}
+
+		// query applies the JSON Pointer, jq program or query expressions to a single JSON document
+		var query documentQuery
+		var err error
+		jsonPath := ""
+		switch {
+		case len(expressions) > 0 && jqFilter != "":
+			return fmt.Errorf("Use either a query argument or --jq, not both")
+		case len(expressions) > 0 && cmd.Flags().Changed("pointer"):
+			return fmt.Errorf("Use either a query argument or --pointer, not both")
+		case cmd.Flags().Changed("pointer"):
+			query = pointerQuery(jsonPointer, fallback, hasDefault)
+		case jqFilter != "":
+			query, err = jqQuery(jqFilter, fallback, hasDefault)
+		case len(expressions) > 1:
+			query, err = labeledQuery(expressions, fallback, hasDefault)
+		default:
+			expression := ""
+			if len(expressions) == 1 {
+				expression = expressions[0]
+			}
+			// Streamed matches are found from the expanded path, so expand it up front
+			if expression, err = expandQuery(queryLanguage, expression); err != nil {
+				return err
+			}
+			if queryLanguage == "jsonpath" {
+				jsonPath = strings.Trim(expression, "\"")
+			}
+			query, err = compileQuery(queryLanguage, expression, fallback, hasDefault)
+		}
+		if err == nil && len(aggregationNames) > 0 {
+			query, err = aggregateQuery(query, aggregationNames)
+		}
 		if err != nil {
-			fmt.Printf("Error reading file: %v\n", err)
-			return
+			return err
 		}
 
-		var jsonData interface{}
-		if err := json.Unmarshal(data, &jsonData); err != nil {
-			fmt.Printf("Error parsing JSON: %v\n", err)
-			return
+		// handle queries and prints a single JSON document
+		handle := func(jsonData interface{}) error {
+			if checkExists {
+				return existsQuery(query, jsonData)
+			}
+			if emitsMatches(jsonPath) {
+				return emitMatches(jsonData, jsonPath, fallback, hasDefault)
+			}
+			result, err := query(jsonData)
+			if err == nil {
+				result, err = decodeBase64Fields(result)
+			}
+			if err != nil {
+				return err
+			}
+			return prettyPrintJSON(result)
+		}
+
+		if showStats {
+			defer printStats()
 		}
 
-		if len(args) == 0 {
-			// No JSONPath provided, print the entire JSON data
-			prettyPrintJSON(jsonData)
-		} else {
-			jsonPath := args[0]
-			// Strip surrounding double quotes if present
-			jsonPath = strings.Trim(jsonPath, "\"")
-			// Use JSONPath to query the data
-			result, err := queryJSONPath(jsonData, jsonPath)
+		switch {
+		case natsURL != "":
+			return readNATS(natsURL, natsSubject, handle)
+		case sseURL != "":
+			return readSSE(sseURL, handle)
+		case sqlitePath != "":
+			return readSQLite(sqlitePath, sqlQuery, handle)
+		case postgresDSN != "":
+			return readPostgres(postgresDSN, sqlQuery, handle)
+		case isArchivePath(filePath):
+			return readArchive(filePath, func(name string, jsonData interface{}) error {
+				result, err := query(jsonData)
+				if err == nil {
+					result, err = decodeBase64Fields(result)
+				}
+				if err != nil {
+					return err
+				}
+				return prettyPrintJSON(map[string]interface{}{"file": name, "result": result})
+			})
+		case isWebSocketURL(filePath):
+			return readWebSocket(filePath, handle)
+		default:
+			jsonData, err := loadDocument()
 			if err != nil {
-				fmt.Printf("Error querying JSONPath: %v\n", err)
-				return
+				return err
 			}
-			prettyPrintJSON(result)
+			return handle(jsonData)
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(readCmd)
+	addResultFlags(readCmd)
 
 	// Define the -f or --file flag
-	readCmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to the JSON file")
-	readCmd.MarkFlagRequired("file")
+	readCmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to the JSON file, a .zip or .tar(.gz) archive of them, an http(s):// URL, or a ws:// or wss:// URL to stream from")
+
+	readCmd.Flags().StringVar(&defaultValue, "default", "", "JSON value to print when the path matches nothing")
 
 	// Enable file path completion for the --file flag
 	readCmd.RegisterFlagCompletionFunc("file", fileCompletion)
@@ -73,23 +189,135 @@ func fileCompletion(cmd *cobra.Command, args []string, toComplete string) ([]str
 	return nil, cobra.ShellCompDirectiveDefault
 }
 
+// loadDocument loads the single JSON document named by the source flags
+func loadDocument() (interface{}, error) {
+	switch {
+	case redisURL != "":
+		return loadRedisDocument(redisURL, redisKey)
+	case dockerContainer != "":
+		return loadDockerDocument(dockerContainer)
+	case k8sResource != "":
+		return loadK8sDocument(k8sResource)
+	case isHTTPURL(filePath):
+		return loadURL(filePath)
+	case filePath != "":
+		return loadFile(filePath)
+	default:
+		return nil, errNoSource
+	}
+}
+
+// loadDocumentOrStdin loads the document named by the source flags, reading JSON from stdin
+// when none is given
+func loadDocumentOrStdin() (interface{}, error) {
+	jsonData, err := loadDocument()
+	if err != errNoSource {
+		return jsonData, err
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading input: %v", err)
+	}
+	return decodeDocument(parseJSON, data)
+}
+
+// loadFile reads a file and decodes it according to its format
+func loadFile(path string) (interface{}, error) {
+	decoder, err := decoderForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading file: %v", err)
+	}
+	return decodeDocument(decoder, data)
+}
+
+// isRegularFile reports whether path names a regular file rather than a pipe or device
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// parseJSON parses JSON data, transcoding BOM-prefixed or UTF-16 input first
+func parseJSON(data []byte) (interface{}, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding input: %v", err)
+	}
+
+	var jsonData interface{}
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return nil, fmt.Errorf("Error parsing JSON: %v", err)
+	}
+	return jsonData, nil
+}
+
 // queryJSONPath queries the JSON data using the provided JSONPath expression
 func queryJSONPath(jsonData interface{}, jsonPath string) (interface{}, error) {
-	result, err := jsonpath.Get(jsonPath, jsonData)
+	// The engines match keys exactly, so case-insensitive paths are resolved by the locator
+	if ignoreCase {
+		return queryLocated(jsonData, jsonPath)
+	}
+
+	engine, err := selectedEngine()
+	if err != nil {
+		return nil, err
+	}
+	result, err := engine(jsonPath, jsonData)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-// prettyPrintJSON formats and prints JSON data
-func prettyPrintJSON(data interface{}) {
-	bytes, err := json.MarshalIndent(data, "", "  ")
+// isNoMatch reports whether a query matched nothing: the path does not exist in the
+// document, or a wildcard, filter, slice or union path selected no values
+func isNoMatch(jsonPath string, result interface{}, err error) bool {
+	if err != nil {
+		return isMissingPathError(err)
+	}
+	items, ok := result.([]interface{})
+	return ok && len(items) == 0 && isMultiMatchPath(jsonPath)
+}
+
+// isMissingPathError reports whether a query error means the path is absent rather than invalid
+func isMissingPathError(err error) bool {
+	if errors.Is(err, errNoMatch) {
+		return true
+	}
+	msg := err.Error()
+	return strings.HasPrefix(msg, "unknown key ") ||
+		strings.HasSuffix(msg, " out of bounds") ||
+		strings.HasPrefix(msg, "unsupported value type ")
+}
+
+// isMultiMatchPath reports whether a path can select any number of values
+func isMultiMatchPath(jsonPath string) bool {
+	return strings.ContainsAny(jsonPath, "*?:,") || strings.Contains(jsonPath, "..")
+}
+
+// prettyPrintJSON formats and prints JSON data in the selected output format
+func prettyPrintJSON(data interface{}) error {
+	bytes, err := formatOutput(data)
 	if err != nil {
-		fmt.Printf("Error formatting JSON: %v\n", err)
-		return
+		return fmt.Errorf("Error formatting output: %v", err)
+	}
+	if binaryOutputFormats[outputFormat] {
+		_, err = output.Write(bytes)
+		return err
+	}
+
+	// Formats that emit one line per item already end in a newline, and print nothing when empty
+	text := string(bytes)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
 	}
-	fmt.Println(string(bytes))
+	_, err = io.WriteString(output, text)
+	return err
 }
 
 // jsonPathCompletion provides dynamic JSONPath suggestions based on the JSON file
@@ -97,6 +325,15 @@ func jsonPathCompletion(cmd *cobra.Command, args []string, toComplete string) ([
 	// Debugging output
 	fmt.Fprintf(os.Stderr, "DEBUG: jsonPathCompletion called with toComplete='%s'\n", toComplete)
 
+	if isSavedQueryReference(toComplete) {
+		return savedQueryCompletion(cmd, args, toComplete)
+	}
+
+	// Suggestions are JSONPath expressions, so offer none for other query languages
+	if queryLanguage != "jsonpath" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
 	// Handle inputs starting with a double quote
 	isQuoted := false
 	if strings.HasPrefix(toComplete, "\"") {
@@ -110,24 +347,9 @@ func jsonPathCompletion(cmd *cobra.Command, args []string, toComplete string) ([
 		isQuoted = true
 	}
 
-	// Get the file path from the --file flag
-	filePath, err := cmd.Flags().GetString("file")
-	if err != nil || filePath == "" {
-		// Cannot provide completions without the file
-		return nil, cobra.ShellCompDirectiveNoFileComp
-	}
-
-	// Read the JSON file
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		// Error reading file, cannot provide completions
-		return nil, cobra.ShellCompDirectiveNoFileComp
-	}
-
-	// Unmarshal JSON into interface{}
-	var jsonData interface{}
-	if err := json.Unmarshal(data, &jsonData); err != nil {
-		// Error parsing JSON
+	// Load the document to draw suggestions from
+	jsonData, ok := completionDocument()
+	if !ok {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
@@ -145,90 +367,152 @@ func jsonPathCompletion(cmd *cobra.Command, args []string, toComplete string) ([
 	return suggestions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
 }
 
+// completionDocument loads the document named by the source flags for completion,
+// reporting false when there is no single document that can safely be read
+func completionDocument() (interface{}, bool) {
+	// Pipes and devices can only be read once, so never consume them for completion
+	if filePath != "" && !isHTTPURL(filePath) && !isRegularFile(filePath) {
+		return nil, false
+	}
+
+	// Archives hold many documents, so there is no single tree to suggest from
+	if isArchivePath(filePath) {
+		return nil, false
+	}
+
+	jsonData, err := loadDocument()
+	if err != nil {
+		// Error reading or parsing the document, cannot provide completions
+		return nil, false
+	}
+	return jsonData, true
+}
+
 // generateJSONPathSuggestions generates suggestions based on the JSON data and current input
 func generateJSONPathSuggestions(jsonData interface{}, toComplete string) []string {
 	// Remove leading '$' and '.' from toComplete
 	path := strings.TrimPrefix(toComplete, "$")
 	path = strings.TrimPrefix(path, ".")
 
-	// Split the path by '.'
+	// Split the path by '.'; the empty token left by '..' marks a recursive descent
 	tokens := strings.Split(path, ".")
 
-	// Start from the root of the JSON data
-	currentData := jsonData
+	// Start from the root of the JSON data. After '..' several nodes can match at once.
+	currentNodes := []interface{}{jsonData}
+	recursive := false
 
 	// Traverse the JSON data according to the tokens, except the last incomplete token
 	for i := 0; i < len(tokens)-1; i++ {
 		token := tokens[i]
 		if token == "" {
+			recursive = true
 			continue
 		}
 
-		// Handle array indices and wildcards
-		if strings.Contains(token, "[") {
-			token, indexPart := splitArrayToken(token)
-			currentData = traverseToArrayElement(currentData, token, indexPart)
-			if currentData == nil {
-				return nil
+		nextNodes := []interface{}{}
+		for _, currentData := range descendNodes(currentNodes, recursive) {
+			// Handle array indices and wildcards
+			if strings.Contains(token, "[") {
+				key, indexPart := splitArrayToken(token)
+				nextNodes = append(nextNodes, traverseToArrayElement(jsonData, currentData, key, indexPart)...)
+				continue
 			}
-			continue
-		}
 
-		switch data := currentData.(type) {
-		case map[string]interface{}:
-			if val, exists := data[token]; exists {
-				currentData = val
-			} else {
-				// Key does not exist
-				return nil
+			// Keys that do not exist or values that are not objects end this branch
+			if data, ok := currentData.(map[string]interface{}); ok {
+				if val, exists := data[token]; exists {
+					nextNodes = append(nextNodes, val)
+				}
 			}
-		default:
-			// Not traversable
+		}
+		if len(nextNodes) == 0 {
 			return nil
 		}
+		currentNodes = nextNodes
+		recursive = false
 	}
 
 	// Handle the last incomplete token
 	incompleteToken := tokens[len(tokens)-1]
 
-	// Handle array indices and wildcards for the incomplete token
-	if strings.Contains(incompleteToken, "[") {
-		token, indexPart := splitArrayToken(incompleteToken)
-		suggestions := suggestArrayIndices(currentData, token, indexPart, toComplete)
-		return suggestions
-	}
-
+	seen := map[string]bool{}
 	suggestions := []string{}
-
-	switch data := currentData.(type) {
-	case map[string]interface{}:
-		for key := range data {
-			if strings.HasPrefix(key, incompleteToken) {
-				suggestion := fmt.Sprintf("%s%s", toComplete, key[len(incompleteToken):])
+	add := func(candidates []string) {
+		for _, suggestion := range candidates {
+			if !seen[suggestion] {
+				seen[suggestion] = true
 				suggestions = append(suggestions, suggestion)
 			}
 		}
-	case []interface{}:
-		// Suggest array indices or '*'
-		if strings.HasPrefix("*", incompleteToken) {
-			suggestion := fmt.Sprintf("%s%s", toComplete, "*"[len(incompleteToken):])
-			suggestions = append(suggestions, suggestion)
+	}
+
+	for _, currentData := range descendNodes(currentNodes, recursive) {
+		// Handle array indices and wildcards for the incomplete token
+		if strings.Contains(incompleteToken, "[") {
+			token, indexPart := splitArrayToken(incompleteToken)
+			add(suggestArrayIndices(currentData, token, indexPart, toComplete))
+			continue
 		}
-		// Suggest numeric indices
-		for i := range data {
-			indexStr := fmt.Sprintf("%d", i)
-			if strings.HasPrefix(indexStr, incompleteToken) {
-				suggestion := fmt.Sprintf("%s%s", toComplete, indexStr[len(incompleteToken):])
-				suggestions = append(suggestions, suggestion)
+
+		switch data := currentData.(type) {
+		case map[string]interface{}:
+			for key := range data {
+				if strings.HasPrefix(key, incompleteToken) {
+					add([]string{fmt.Sprintf("%s%s", toComplete, key[len(incompleteToken):])})
+				}
+			}
+		case []interface{}:
+			// Below '..' arrays only contribute the keys of their elements
+			if recursive {
+				continue
+			}
+			// Suggest array indices or '*'
+			if strings.HasPrefix("*", incompleteToken) {
+				add([]string{fmt.Sprintf("%s%s", toComplete, "*"[len(incompleteToken):])})
+			}
+			// Suggest numeric indices
+			for i := range data {
+				indexStr := fmt.Sprintf("%d", i)
+				if strings.HasPrefix(indexStr, incompleteToken) {
+					add([]string{fmt.Sprintf("%s%s", toComplete, indexStr[len(incompleteToken):])})
+				}
 			}
+		default:
+			// Cannot suggest further
 		}
-	default:
-		// Cannot suggest further
 	}
 
+	sort.Strings(suggestions)
 	return suggestions
 }
 
+// descendNodes returns the nodes themselves, or with recursive set, the nodes and everything below them
+func descendNodes(nodes []interface{}, recursive bool) []interface{} {
+	if !recursive {
+		return nodes
+	}
+
+	result := []interface{}{}
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		result = append(result, node)
+		switch v := node.(type) {
+		case map[string]interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return result
+}
+
 // suggestArrayIndices suggests indices and wildcards for arrays
 func suggestArrayIndices(currentData interface{}, token string, indexPart string, toComplete string) []string {
 	suggestions := []string{}
@@ -284,8 +568,9 @@ func splitArrayToken(token string) (string, string) {
 	return token[:idx], token[idx:]
 }
 
-// traverseToArrayElement navigates to the specified array element
-func traverseToArrayElement(currentData interface{}, token string, indexPart string) interface{} {
+// traverseToArrayElement navigates to the elements selected by one or more bracketed
+// indices, slices, wildcards or unions, e.g. "[-1]", "[2:5]", "[*][0]" or "['a','b']"
+func traverseToArrayElement(root interface{}, currentData interface{}, token string, indexPart string) []interface{} {
 	// Handle the object key before the array index
 	if token != "" {
 		switch data := currentData.(type) {
@@ -302,25 +587,31 @@ func traverseToArrayElement(currentData interface{}, token string, indexPart str
 		}
 	}
 
-	// Handle the array index or wildcard
-	if indexPart != "" {
-		indexPart = strings.Trim(indexPart, "[]")
-		switch data := currentData.(type) {
-		case []interface{}:
-			if indexPart == "*" {
-				// Wildcard, keep currentData as is
-				return currentData
-			} else {
-				index, err := strconv.Atoi(indexPart)
-				if err != nil || index < 0 || index >= len(data) {
-					return nil
-				}
-				currentData = data[index]
-			}
-		default:
+	// Apply each bracket in turn to every element selected so far
+	nodes := []interface{}{currentData}
+	for indexPart != "" {
+		end, err := closingBracket(indexPart)
+		if err != nil {
 			return nil
 		}
-	}
+		segment, err := parseBracket(indexPart[1:end])
+		if err != nil {
+			return nil
+		}
+		indexPart = indexPart[end+1:]
 
-	return currentData
+		selected := []interface{}{}
+		for _, node := range nodes {
+			matches, err := selectChildren(root, node, location{}, segment)
+			if err != nil {
+				return nil
+			}
+			for _, match := range matches {
+				value, _ := getAt(node, match)
+				selected = append(selected, value)
+			}
+		}
+		nodes = selected
+	}
+	return nodes
 }
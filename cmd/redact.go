@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	redactPaths       []string
+	redactReplacement string
+	redactNoBuiltin   bool
+)
+
+// secretKeyTerms are the words that end member names which usually hold secrets. Names are
+// split into lowercase words first, so api_key, apiKey and API-KEY all end in "api key", while
+// token_count, max_tokens and secretary end in other words.
+var secretKeyTerms = []string{
+	"password", "passwd", "passphrase", "pwd", "secret", "token", "api key", "apikey",
+	"access key", "accesskey", "private key", "privatekey", "credential", "credentials",
+	"authorization", "cookie", "session id", "sessionid", "connection string", "connectionstring", "dsn",
+}
+
+// nameWordBoundary matches the separators and case changes between the words of a member name
+var nameWordBoundary = regexp.MustCompile(`[^A-Za-z0-9]+|([a-z0-9])([A-Z])|([A-Z])([A-Z][a-z])`)
+
+// redactCmd represents the redact command
+var redactCmd = &cobra.Command{
+	Use:   "redact",
+	Short: "Replace secrets with a placeholder",
+	Long: `Replace the values the --paths expressions match, and any member whose name ends in
+a word that usually names a secret (password, secret, token, api_key, access_key,
+private_key, credentials, authorization, cookie, session_id, connection_string, dsn and the
+like), with --replacement. Prints the sanitized copy, or rewrites the file with -i. Use --no-builtin to
+redact only --paths:
+
+  $ mycli redact -f config.json
+  $ mycli redact -f config.json --paths '$..password,$..token' --replacement '***'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPaths := []string{}
+		for _, list := range redactPaths {
+			jsonPaths = append(jsonPaths, splitPathList(list)...)
+		}
+		if redactNoBuiltin && len(jsonPaths) == 0 {
+			return fmt.Errorf("Please specify what to redact with --paths when using --no-builtin")
+		}
+
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		locations, err := locatePaths(jsonData, jsonPaths)
+		if err != nil {
+			return err
+		}
+		if !redactNoBuiltin {
+			locations = append(locations, secretLocations(jsonData)...)
+		}
+
+		for _, loc := range outermostLocations(locations) {
+			jsonData = setAt(jsonData, loc, redactReplacement)
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(redactCmd)
+
+	addEditFlags(redactCmd)
+	redactCmd.Flags().StringArrayVar(&redactPaths, "paths", nil, "Comma-separated JSONPaths whose values to redact (repeatable)")
+	redactCmd.Flags().StringVar(&redactReplacement, "replacement", "***", "Text that replaces each redacted value")
+	redactCmd.Flags().BoolVar(&redactNoBuiltin, "no-builtin", false, "Do not redact members just because their names look like secrets")
+}
+
+// splitPathList splits a comma-separated list of paths, leaving commas inside brackets,
+// parentheses and quotes, as in unions and filters, alone
+func splitPathList(list string) []string {
+	paths := []string{}
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			paths = append(paths, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	return append(paths, strings.TrimSpace(list[start:]))
+}
+
+// locatePaths returns the locations every path matches
+func locatePaths(jsonData interface{}, jsonPaths []string) ([]location, error) {
+	locations := []location{}
+	for _, jsonPath := range jsonPaths {
+		matches, err := locateJSONPath(jsonData, jsonPath)
+		if err != nil {
+			return nil, jsonPathError(jsonPath, err)
+		}
+		locations = append(locations, matches...)
+	}
+	return locations, nil
+}
+
+// secretLocations returns every member whose name ends in one of secretKeyTerms
+func secretLocations(jsonData interface{}) []location {
+	locations := []location{}
+	for _, loc := range descendants(jsonData, location{}) {
+		key, ok := loc.lastKey()
+		if ok && isSecretKey(key) {
+			locations = append(locations, loc)
+		}
+	}
+	return locations
+}
+
+// isSecretKey reports whether the words of a member name end with one of secretKeyTerms
+func isSecretKey(key string) bool {
+	words := strings.ToLower(strings.TrimSpace(nameWordBoundary.ReplaceAllString(key, "$1$3 $2$4")))
+	for _, term := range secretKeyTerms {
+		if words == term || strings.HasSuffix(words, " "+term) {
+			return true
+		}
+	}
+	return false
+}
+
+// outermostLocations sorts locations, dropping duplicates and any inside another, so
+// replacing each value leaves none of the others stranded
+func outermostLocations(locations []location) []location {
+	sort.Slice(locations, func(i, j int) bool {
+		return compareValues([]interface{}(locations[i]), []interface{}(locations[j])) < 0
+	})
+	outermost := []location{}
+	for _, loc := range locations {
+		if len(outermost) > 0 && isPrefixLocation(outermost[len(outermost)-1], loc) {
+			continue
+		}
+		outermost = append(outermost, loc)
+	}
+	return outermost
+}
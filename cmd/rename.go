@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var renameForce bool
+
+// renameCmd represents the rename command
+var renameCmd = &cobra.Command{
+	Use:   "rename <jsonpath> <new-name>",
+	Short: "Rename the object keys a path matches",
+	Long: `Rename the object member at every location a path matches, keeping its value, and
+print the modified document, or rewrite the file with -i. Renaming onto a key that already
+exists is refused unless --force is given:
+
+  $ mycli rename -f config.json '$.services[*].old_name' name -i`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPath, newName := args[0], args[1]
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+
+		if err := renameMembers(jsonData, jsonPath, newName, renameForce); err != nil {
+			return err
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+
+	addEditFlags(renameCmd)
+	renameCmd.Flags().BoolVar(&renameForce, "force", false, "Replace a member that already has the new name")
+}
+
+// renameMembers gives every object member a path matches the new name, replacing a member
+// already called that only when force is set
+func renameMembers(jsonData interface{}, jsonPath string, newName string, force bool) error {
+	locations, err := locateJSONPath(jsonData, jsonPath)
+	if err != nil {
+		return jsonPathError(jsonPath, err)
+	}
+	if len(locations) == 0 {
+		return fmt.Errorf("Error renaming %s: the path matched nothing", jsonPath)
+	}
+
+	for _, loc := range locations {
+		oldName, ok := loc.lastKey()
+		if !ok {
+			return fmt.Errorf("Error renaming %s: only object members can be renamed", loc)
+		}
+		if oldName == newName {
+			continue
+		}
+		parent, _ := getAt(jsonData, loc[:len(loc)-1])
+		object := parent.(map[string]interface{})
+		if _, exists := object[newName]; exists && !force {
+			return fmt.Errorf("Error renaming %s: %s already exists (use --force to replace it)", loc, appendLocation(loc[:len(loc)-1], newName))
+		}
+		object[newName] = object[oldName]
+		delete(object, oldName)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -12,9 +13,22 @@ var rootCmd = &cobra.Command{
 	Short: "A CLI tool to read JSON files",
 }
 
+// errFalseResult ends a command whose answer is no with exit status 1, without an error message
+var errFalseResult = errors.New("false")
+
 // Execute runs the root command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	attachResultFlags()
+	rootCmd.SetArgs(joinArgPairs(os.Args[1:]))
+	err := rootCmd.Execute()
+	succeeded := err == nil || err == errFalseResult
+	if flushErr := flushOutput(succeeded); succeeded && flushErr != nil {
+		err = flushErr
+	}
+	if err == errFalseResult {
+		os.Exit(1)
+	}
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -23,4 +37,10 @@ func Execute() {
 func init() {
 	// Enable the built-in completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = false
+
+	// Errors are printed once by Execute, without the usage text
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	cobra.OnInitialize(setupOutput)
 }
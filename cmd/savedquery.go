@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// savedQueryName matches the names queries can be saved under
+var savedQueryName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// queryCmd groups the commands that manage saved queries
+var queryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Manage named queries saved in the config file",
+	Long: `Manage named queries saved in the config file. Saved queries run in place
+of an expression by prefixing the name with '@'. Placeholders {0}, {1}, ... make a
+query take arguments:
+
+  $ mycli query save pod-names '$.items[*].metadata.name'
+  $ mycli read -f pods.json @pod-names
+  $ mycli query save by-label "\$.items[?(@.metadata.labels.app=='{0}')]"
+  $ mycli read -f pods.json '@by-label(nginx)'`,
+}
+
+// querySaveCmd saves or replaces a named query
+var querySaveCmd = &cobra.Command{
+	Use:   "save <name> <expression>",
+	Short: "Save a query under a name",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, expression := strings.TrimPrefix(args[0], "@"), args[1]
+		if !savedQueryName.MatchString(name) {
+			return fmt.Errorf("invalid query name %q: use letters, digits, '.', '_' and '-'", name)
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if config.Queries == nil {
+			config.Queries = map[string]string{}
+		}
+		config.Queries[name] = expression
+		return saveConfig(config)
+	},
+}
+
+// queryListCmd prints the saved queries
+var queryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved queries",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		for _, name := range sortedQueryNames(config) {
+			fmt.Fprintf(output, "@%s\t%s\n", name, config.Queries[name])
+		}
+		return nil
+	},
+}
+
+// queryDeleteCmd removes a saved query
+var queryDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Delete a saved query",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: savedQueryCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], "@")
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := config.Queries[name]; !ok {
+			return fmt.Errorf("no saved query named %s", name)
+		}
+		delete(config.Queries, name)
+		return saveConfig(config)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.AddCommand(querySaveCmd, queryListCmd, queryDeleteCmd)
+}
+
+// sortedQueryNames returns the names of the saved queries in order
+func sortedQueryNames(config *cliConfig) []string {
+	names := make([]string, 0, len(config.Queries))
+	for name := range config.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isSavedQueryReference reports whether an expression names a saved query
+func isSavedQueryReference(expression string) bool {
+	return strings.HasPrefix(expression, "@")
+}
+
+// resolveSavedQuery returns the expression saved under an @name reference, filling the
+// {0}, {1}, ... placeholders of parameterized queries from @name(arg, ...)
+func resolveSavedQuery(reference string) (string, error) {
+	match := savedQueryReference.FindStringSubmatch(reference)
+	if match == nil {
+		return "", fmt.Errorf("invalid saved query reference %s: expected @name or @name(arg, ...)", reference)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	name := match[1]
+	expression, ok := config.Queries[name]
+	if !ok {
+		return "", fmt.Errorf("no saved query named %s", name)
+	}
+
+	var args []string
+	if match[2] != "" {
+		args = splitMacroArgs(match[3])
+	}
+	if want := macroParameterCount(expression); len(args) != want {
+		return "", fmt.Errorf("saved query %s expects %s, got %d", name, plural(want, "argument"), len(args))
+	}
+	return expandMacro(expression, args), nil
+}
+
+// macroPlaceholder matches the {n} parameters of a saved query
+var macroPlaceholder = regexp.MustCompile(`\{(\d+)\}`)
+
+// savedQueryReference matches @name with optional parenthesized arguments
+var savedQueryReference = regexp.MustCompile(`^@([A-Za-z0-9_.-]+)(\((.*)\))?$`)
+
+// macroParameterCount returns how many arguments a saved query takes: one more than its
+// highest placeholder index
+func macroParameterCount(expression string) int {
+	count := 0
+	for _, match := range macroPlaceholder.FindAllStringSubmatch(expression, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil && n+1 > count {
+			count = n + 1
+		}
+	}
+	return count
+}
+
+// splitMacroArgs splits arguments on commas; quoted arguments may contain commas
+func splitMacroArgs(list string) []string {
+	args := []string{}
+	for _, arg := range splitUnion(list) {
+		arg = strings.TrimSpace(arg)
+		if isQuoted(arg) {
+			if unquoted, err := unquoteKey(arg); err == nil {
+				arg = unquoted
+			}
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// expandMacro fills placeholders with arguments. Inside a quoted string, quotes and
+// backslashes in the argument are escaped so it cannot end the string early.
+func expandMacro(expression string, args []string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		if loc := macroPlaceholder.FindStringSubmatchIndex(expression[i:]); loc != nil && loc[0] == 0 {
+			n, _ := strconv.Atoi(expression[i+loc[2] : i+loc[3]])
+			arg := args[n]
+			if quote != 0 {
+				arg = strings.NewReplacer(`\`, `\\`, string(quote), `\`+string(quote)).Replace(arg)
+			}
+			b.WriteString(arg)
+			i += loc[1] - 1
+			continue
+		}
+
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(expression) {
+				b.WriteByte(c)
+				i++
+				c = expression[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// savedQueryCompletion suggests the names of saved queries, keeping any '@' the user typed
+func savedQueryCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	prefix := ""
+	if isSavedQueryReference(toComplete) {
+		prefix = "@"
+	}
+	// Parameterized queries complete up to the opening parenthesis of their arguments
+	directive := cobra.ShellCompDirectiveNoFileComp
+	suggestions := []string{}
+	for _, name := range sortedQueryNames(config) {
+		if !strings.HasPrefix(prefix+name, toComplete) {
+			continue
+		}
+		if prefix != "" && macroParameterCount(config.Queries[name]) > 0 {
+			suggestions = append(suggestions, prefix+name+"(")
+			directive |= cobra.ShellCompDirectiveNoSpace
+			continue
+		}
+		suggestions = append(suggestions, prefix+name)
+	}
+	return suggestions, directive
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchKey   string
+	searchValue string
+	searchRegex bool
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Find where keys or values occur in a document",
+	Long: `Find where object keys or scalar values occur in a document, printing the JSONPath
+of each match. Given both --key and --value, a match needs both:
+
+  $ mycli search -f stack.json --value us-east-1
+  $ mycli search -f stack.json --key '^aws_' --regex`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchKey == "" && searchValue == "" {
+			return fmt.Errorf("Please specify what to search for with --key or --value")
+		}
+
+		matchKey, err := searchMatcher(searchKey)
+		if err != nil {
+			return err
+		}
+		matchValue, err := searchMatcher(searchValue)
+		if err != nil {
+			return err
+		}
+
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+
+		for _, loc := range descendants(jsonData, location{}) {
+			if searchKey != "" {
+				key, ok := loc.lastKey()
+				if !ok || !matchKey(key) {
+					continue
+				}
+			}
+			if searchValue != "" {
+				value, _ := getAt(jsonData, loc)
+				if !isScalar(value) || !matchValue(searchText(value)) {
+					continue
+				}
+			}
+			fmt.Fprintln(output, loc)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to search (defaults to JSON on stdin)")
+	searchCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	searchCmd.Flags().StringVar(&searchKey, "key", "", "Find object keys equal to this")
+	searchCmd.Flags().StringVar(&searchValue, "value", "", "Find scalar values whose text is equal to this")
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "Treat --key and --value as regular expressions matched anywhere in the text")
+}
+
+// searchMatcher builds the test for one search term, honoring --regex and --ignore-case
+func searchMatcher(term string) (func(string) bool, error) {
+	if !searchRegex {
+		if ignoreCase {
+			return func(text string) bool { return strings.EqualFold(text, term) }, nil
+		}
+		return func(text string) bool { return text == term }, nil
+	}
+
+	if ignoreCase {
+		term = "(?i)" + term
+	}
+	re, err := regexp.Compile(term)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing regular expression: %v", err)
+	}
+	return re.MatchString, nil
+}
+
+// searchText is the text a scalar value is matched by, with null spelled out
+func searchText(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return cellText(value)
+}
+
+// lastKey returns the object key a location ends in
+func (loc location) lastKey() (string, bool) {
+	if len(loc) == 0 {
+		return "", false
+	}
+	key, ok := loc[len(loc)-1].(string)
+	return key, ok
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var setString bool
+
+// setCmd represents the set command
+var setCmd = &cobra.Command{
+	Use:   "set <jsonpath> <value>",
+	Short: "Set the value at a path",
+	Long: `Set the value at every location a path matches and print the modified document, or
+rewrite the file with -i. The value is parsed as JSON unless --string is given. When the
+path matches nothing, its last key is added to each object the rest of the path matches, or
+a path of plain keys and indices is created along with any missing objects:
+
+  $ mycli set -f config.json '$.server.port' 8443 -i
+  $ mycli set -f config.json '$.server.host' --string example.com`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonPath := args[0]
+		var value interface{} = args[1]
+		if !setString {
+			if err := json.Unmarshal([]byte(args[1]), &value); err != nil {
+				return fmt.Errorf("Error parsing value as JSON: %v (use --string to set it as text)", err)
+			}
+		}
+
+		jsonData, err := loadEditDocument()
+		if err != nil {
+			return err
+		}
+		if jsonData, err = setPath(jsonData, jsonPath, value); err != nil {
+			return err
+		}
+		return saveEditedDocument(jsonData)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+
+	addEditFlags(setCmd)
+	setCmd.Flags().BoolVar(&setString, "string", false, "Set the value as a string instead of parsing it as JSON")
+}
+
+// setPath sets the value at every match of a path. When nothing matches, a missing last key
+// is added to every object the rest of the path matches, and failing that a path naming a
+// single location is created.
+func setPath(jsonData interface{}, jsonPath string, value interface{}) (interface{}, error) {
+	locations, err := locateJSONPath(jsonData, jsonPath)
+	if err != nil {
+		return nil, jsonPathError(jsonPath, err)
+	}
+	if len(locations) == 0 {
+		if locations, err = newKeyLocations(jsonData, jsonPath); err != nil {
+			return nil, jsonPathError(jsonPath, err)
+		}
+	}
+	for _, loc := range locations {
+		jsonData = setAt(jsonData, loc, value)
+	}
+	if len(locations) > 0 {
+		return jsonData, nil
+	}
+
+	loc, ok := singularLocation(jsonPath)
+	if !ok {
+		return nil, fmt.Errorf("Error setting %s: the path matched nothing, and only paths of plain keys and indices can be created", jsonPath)
+	}
+	jsonData, err = createAt(jsonData, loc, value, false)
+	if err != nil {
+		return nil, fmt.Errorf("Error setting %s: %v", jsonPath, err)
+	}
+	return jsonData, nil
+}
+
+// newKeyLocations returns where a path ending in a single key would be in each object the
+// rest of the path matches
+func newKeyLocations(jsonData interface{}, jsonPath string) ([]location, error) {
+	segments, err := parsePathSegments(jsonPath)
+	if err != nil || len(segments) == 0 {
+		return nil, err
+	}
+	last := segments[len(segments)-1]
+	if last.recursive || last.kind != selectKeys || len(last.keys) != 1 {
+		return nil, nil
+	}
+
+	parentPath := "$"
+	for _, segment := range segments[:len(segments)-1] {
+		parentPath += segment.text
+	}
+	parents, err := locateJSONPath(jsonData, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []location{}
+	for _, parent := range parents {
+		if node, _ := getAt(jsonData, parent); isObject(node) {
+			locations = append(locations, appendLocation(parent, last.keys[0]))
+		}
+	}
+	return locations, nil
+}
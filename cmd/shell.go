@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var shellVariable string
+
+func init() {
+	resultFlags.StringVar(&shellVariable, "shell-var", "result", "Variable name, used exactly as given, for shell output when the result is not an object")
+}
+
+// shellIdentifier matches a valid shell variable name
+var shellIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// formatShell renders a result as shell assignments for eval: object keys become variables,
+// nested keys are joined with '_' and arrays of scalars become bash arrays. A result that is not
+// an object is assigned to the --shell-var name exactly as given.
+func formatShell(data interface{}) ([]byte, error) {
+	variableName := envVariableName
+	if _, ok := data.(map[string]interface{}); !ok {
+		if !shellIdentifier.MatchString(shellVariable) {
+			return nil, fmt.Errorf("invalid --shell-var %q: use letters, digits and underscores, not starting with a digit", shellVariable)
+		}
+		variableName = func(key string) string {
+			if key == "" {
+				return shellVariable
+			}
+			return shellVariable + "_" + envUnsafeKeyChars.ReplaceAllString(key, "_")
+		}
+	}
+
+	lines := []string{}
+	sources := map[string]location{}
+	var walk func(loc location, value interface{}) error
+	walk = func(loc location, value interface{}) error {
+		if array, ok := value.([]interface{}); (ok && !isScalarArray(array)) || isObject(value) {
+			for _, child := range childLocations(value, loc) {
+				item, _ := getAt(value, child[len(loc):])
+				if err := walk(child, item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		// Keys that differ only in characters the shell does not allow end up with the same name
+		parts := make([]string, len(loc))
+		for i, key := range loc {
+			parts[i] = fmt.Sprint(key)
+		}
+		name := variableName(strings.Join(parts, "_"))
+		if other, exists := sources[name]; exists {
+			return fmt.Errorf("shell output would assign %s for both %s and %s", name, other, loc)
+		}
+		sources[name] = loc
+
+		if array, ok := value.([]interface{}); ok {
+			elements := make([]string, len(array))
+			for i, item := range array {
+				elements[i] = shellWord(cellText(item))
+			}
+			lines = append(lines, name+"=("+strings.Join(elements, " ")+")")
+			return nil
+		}
+		line := name + "=" + shellQuote(cellText(value))
+		if envExport {
+			line = "export " + line
+		}
+		lines = append(lines, line)
+		return nil
+	}
+
+	if err := walk(location{}, data); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	sort.Strings(lines)
+
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// isScalarArray reports whether an array holds no objects or arrays
+func isScalarArray(items []interface{}) bool {
+	for _, item := range items {
+		if !isScalar(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// shellWord quotes a value as a single shell word, writing empty strings as an empty quoted word
+func shellWord(value string) string {
+	if value == "" {
+		return "''"
+	}
+	return shellQuote(value)
+}
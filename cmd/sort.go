@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sortBy   string
+	sortDesc bool
+)
+
+// sortCmd represents the sort command
+var sortCmd = &cobra.Command{
+	Use:   "sort [jsonpath]",
+	Short: "Sort an array, optionally by the value at a path within each item",
+	Long: `Sort the array at a path, or the whole document, by the items themselves or by the
+value --by selects in each. Values of different types order as null, booleans, numbers,
+strings, arrays then objects; items that compare equal keep their original order:
+
+  $ mycli sort -f pods.json '$.items' --by '$.metadata.creationTimestamp' --desc`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+		value, err := queryArgument(jsonData, args)
+		if err != nil {
+			return err
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("sort requires an array, got %s", jsonTypeName(value))
+		}
+
+		byQuery, err := compileQuery(queryLanguage, sortBy, nil, true)
+		if err != nil {
+			return err
+		}
+		keys := make([]interface{}, len(items))
+		for i, item := range items {
+			if keys[i], err = byQuery(item); err != nil {
+				return err
+			}
+		}
+
+		order := make([]int, len(items))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			c := compareValues(keys[order[i]], keys[order[j]])
+			if sortDesc {
+				return c > 0
+			}
+			return c < 0
+		})
+
+		sorted := make([]interface{}, len(items))
+		for i, index := range order {
+			sorted[i] = items[index]
+		}
+		return prettyPrintJSON(sorted)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sortCmd)
+	addResultFlags(sortCmd)
+
+	sortCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	sortCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	sortCmd.Flags().StringVar(&sortBy, "by", "", "Path, within each item, of the value to sort by")
+	sortCmd.Flags().BoolVar(&sortDesc, "desc", false, "Sort in descending order")
+}
+
+// typeRank orders values of different JSON types: null, boolean, number, string, array, object
+func typeRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case string:
+		return 3
+	case []interface{}:
+		return 4
+	case map[string]interface{}:
+		return 5
+	default:
+		return 2
+	}
+}
+
+// compareValues orders two decoded values, returning a negative number, zero or a positive
+// number. Arrays compare element by element and objects by their sorted keys, then values.
+func compareValues(a, b interface{}) int {
+	if rankA, rankB := typeRank(a), typeRank(b); rankA != rankB {
+		return rankA - rankB
+	}
+
+	switch x := a.(type) {
+	case nil:
+		return 0
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return len(x) - len(y)
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		keysX, keysY := sortedKeys(x), sortedKeys(y)
+		if c := compareValues(stringsToValues(keysX), stringsToValues(keysY)); c != 0 {
+			return c
+		}
+		for _, key := range keysX {
+			if c := compareValues(x[key], y[key]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	default:
+		numberA, _ := numberValue(a)
+		numberB, _ := numberValue(b)
+		switch {
+		case numberA < numberB:
+			return -1
+		case numberA > numberB:
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// sortedKeys returns the keys of an object in sorted order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringsToValues converts strings to decoded values so they can be compared as an array
+func stringsToValues(strs []string) []interface{} {
+	values := make([]interface{}, len(strs))
+	for i, s := range strs {
+		values[i] = s
+	}
+	return values
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var sqlQuery string
+
+func init() {
+	readCmd.Flags().StringVar(&sqlQuery, "sql", "", "SQL statement whose rows are queried as JSON documents")
+}
+
+// readSQLRows runs a statement and passes each row to the handler as a JSON document.
+// A single selected column is parsed as JSON; multiple columns become an object keyed by column name.
+func readSQLRows(db *sql.DB, query string, handle documentHandler) error {
+	if query == "" {
+		return fmt.Errorf("Please specify a statement using the --sql flag.")
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("Error running SQL: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return fmt.Errorf("Error reading columns: %v", err)
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("Error reading row: %v", err)
+		}
+
+		jsonData, err := rowDocument(columns, values)
+		if err == nil {
+			err = handle(jsonData)
+		}
+		if err != nil {
+			// Report the failing row and carry on with the rest
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error reading rows: %v", err)
+	}
+	return nil
+}
+
+// rowDocument converts a scanned row into a JSON document
+func rowDocument(columns []*sql.ColumnType, values []interface{}) (interface{}, error) {
+	if len(columns) == 1 {
+		switch v := values[0].(type) {
+		case []byte:
+			return parseJSON(v)
+		case string:
+			return parseJSON([]byte(v))
+		}
+		return sqlValue(columns[0], values[0])
+	}
+
+	row := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		value, err := sqlValue(column, values[i])
+		if err != nil {
+			return nil, err
+		}
+		row[column.Name()] = value
+	}
+	return row, nil
+}
+
+// sqlValue converts a driver value into its JSON equivalent, parsing columns declared as JSON
+func sqlValue(column *sql.ColumnType, value interface{}) (interface{}, error) {
+	isJSON := strings.Contains(strings.ToUpper(column.DatabaseTypeName()), "JSON")
+
+	switch v := value.(type) {
+	case []byte:
+		if isJSON {
+			return parseJSON(v)
+		}
+		return string(v), nil
+	case string:
+		if isJSON {
+			return parseJSON([]byte(v))
+		}
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+
+	_ "modernc.org/sqlite"
+)
+
+var sqlitePath string
+
+func init() {
+	readCmd.Flags().StringVar(&sqlitePath, "sqlite", "", "SQLite database file to run the --sql statement against")
+}
+
+// readSQLite runs the --sql statement against a SQLite database and passes each row to the handler
+func readSQLite(path string, query string, handle documentHandler) error {
+	// The driver reports a missing file confusingly, so check it up front
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("Error opening SQLite database: %v", err)
+	}
+
+	// Escape the path so characters such as ? and # are not read as part of the URI
+	dsn := url.URL{Scheme: "file", Path: path, OmitHost: true, RawQuery: "mode=ro"}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return fmt.Errorf("Error opening SQLite database: %v", err)
+	}
+	defer db.Close()
+
+	return readSQLRows(db, query, handle)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var sseURL string
+
+func init() {
+	readCmd.Flags().StringVar(&sseURL, "sse", "", "Server-Sent Events endpoint to stream JSON data payloads from")
+}
+
+// readSSE consumes a Server-Sent Events stream and passes each event's data payload to the handler
+func readSSE(url string, handle documentHandler) error {
+	ctx, cancel := streamContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("Error creating request: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	transport, err := newTransport()
+	if err != nil {
+		return err
+	}
+
+	// No overall timeout: the stream stays open until a limit is reached
+	client := &http.Client{Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("Error connecting to SSE endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error connecting to SSE endpoint: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var data []string
+	received := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// A blank line dispatches the event accumulated so far
+		if line == "" {
+			if len(data) > 0 {
+				received++
+				handleStreamMessage([]byte(strings.Join(data, "\n")), handle)
+				data = nil
+				if streamLimitReached(received) {
+					return nil
+				}
+			}
+			continue
+		}
+
+		// Only data fields carry the payload; comments and other fields are skipped
+		field, value, _ := strings.Cut(line, ":")
+		if field == "data" {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("Error reading SSE stream: %v", err)
+	}
+	return nil
+}
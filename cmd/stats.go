@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+var showStats bool
+
+// queryStats accumulates the figures reported by --stats
+var queryStats struct {
+	matches   int
+	bytesRead int
+	parseTime time.Duration
+	queryTime time.Duration
+}
+
+func init() {
+	readCmd.Flags().BoolVar(&showStats, "stats", false, "Print match count, bytes read, parse time and query time to stderr after the result")
+}
+
+// decodeDocument decodes raw input, recording its size and parse time for --stats
+func decodeDocument(decoder documentDecoder, data []byte) (interface{}, error) {
+	start := time.Now()
+	jsonData, err := decoder(data)
+	queryStats.parseTime += time.Since(start)
+	queryStats.bytesRead += len(data)
+	return jsonData, err
+}
+
+// countMatches returns how many values a query result represents
+func countMatches(jsonPath string, result interface{}) int {
+	if items, ok := result.([]interface{}); ok && isMultiMatchPath(jsonPath) {
+		return len(items)
+	}
+	return 1
+}
+
+// printStats writes the --stats summary to stderr
+func printStats() {
+	fmt.Fprintf(os.Stderr, "matches: %d, bytes read: %d, parse time: %s, query time: %s\n",
+		queryStats.matches, queryStats.bytesRead,
+		queryStats.parseTime.Round(time.Microsecond), queryStats.queryTime.Round(time.Microsecond))
+}
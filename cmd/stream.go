@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+var (
+	streamCount    int
+	streamDuration time.Duration
+)
+
+func init() {
+	// Limits shared by the streaming input sources
+	readCmd.Flags().IntVar(&streamCount, "count", 0, "Stop after receiving this many messages from a stream (0 for no limit)")
+	readCmd.Flags().DurationVar(&streamDuration, "duration", 0, "Stop reading from a stream after this long (0 for no limit)")
+}
+
+// isStreamingSource reports whether the selected input delivers messages until interrupted
+func isStreamingSource() bool {
+	return natsURL != "" || sseURL != "" || isWebSocketURL(filePath)
+}
+
+// streamContext returns a context that ends on interrupt or when --duration elapses
+func streamContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if streamDuration <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, streamDuration)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// streamLimitReached reports whether --count messages have been received
+func streamLimitReached(received int) bool {
+	return streamCount > 0 && received >= streamCount
+}
+
+// handleStreamMessage parses a message payload and passes it to the handler.
+// Failures are reported on stderr so a single bad message does not end the stream.
+func handleStreamMessage(data []byte, handle documentHandler) {
+	jsonData, err := decodeDocument(parseJSON, data)
+	if err == nil {
+		err = handle(jsonData)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// emitsMatches reports whether matches of a query are written one per line as they are found
+// rather than collected into a single result first
+func emitsMatches(jsonPath string) bool {
+	if !jsonLines || templateRequested() || len(aggregationNames) > 0 || !isMultiMatchPath(jsonPath) {
+		return false
+	}
+	_, err := parsePathSegments(jsonPath)
+	return err == nil
+}
+
+// emitMatches writes each match of a multi-match query on its own line. When the locator finds
+// the same matches in the same order as the selected engine, they are written as soon as they are
+// found, so downstream tools such as head and grep see results without waiting for the whole set.
+func emitMatches(jsonData interface{}, jsonPath string, fallback interface{}, hasDefault bool) error {
+	// Report syntax errors against the whole path rather than a single filter
+	if err := validateJSONPath(jsonPath); err != nil {
+		return jsonPathError(jsonPath, err)
+	}
+
+	start := time.Now()
+	var writing time.Duration
+	found := 0
+	write := func(value interface{}) error {
+		found++
+		writeStart := time.Now()
+		defer func() { writing += time.Since(writeStart) }()
+		return writeMatch(value)
+	}
+
+	var err error
+	if locatorMatchesEngine(jsonPath) {
+		err = walkJSONPath(jsonData, jsonPath, func(loc location, value interface{}) error {
+			return write(value)
+		})
+	} else {
+		var result interface{}
+		if result, err = queryJSONPath(jsonData, jsonPath); err == nil {
+			items, _ := result.([]interface{})
+			for _, item := range items {
+				if err = write(item); err != nil {
+					break
+				}
+			}
+		}
+	}
+	queryStats.queryTime += time.Since(start) - writing
+	queryStats.matches += found
+	if err != nil && !isMissingPathError(err) {
+		return jsonPathError(jsonPath, err)
+	}
+
+	if found == 0 && hasDefault {
+		return writeMatch(fallback)
+	}
+	return nil
+}
+
+// locatorMatchesEngine reports whether walking a path with the locator yields what the selected
+// engine returns for it: either the engine hands the path to the locator itself, or it is the
+// RFC 9535 engine, which orders object members and descendants the same way. PaesslerAG visits
+// object members in Go's map order, so its results can only be written once it has finished.
+func locatorMatchesEngine(jsonPath string) bool {
+	if ignoreCase || jsonPathEngineName == "rfc9535" {
+		return true
+	}
+	segments, err := parsePathSegments(jsonPath)
+	return err == nil && needsLocator(segments)
+}
+
+// writeMatch decodes and writes a single match as one line of output
+func writeMatch(value interface{}) error {
+	// --decode-base64 paths address the result array, so decode the match as its only element
+	decoded, err := decodeBase64Fields([]interface{}{value})
+	if err != nil {
+		return err
+	}
+	line, err := jsonLine(decoded.([]interface{})[0])
+	if err != nil {
+		return err
+	}
+	_, err = output.Write(line)
+	return err
+}
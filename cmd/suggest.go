@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxKeySuggestions limits how many alternatives a did-you-mean hint lists
+const maxKeySuggestions = 3
+
+// unknownKeyHint replays a path that matched nothing to find the first key that does not
+// exist, returning a message naming the closest keys at that level, or "" if none is found
+func unknownKeyHint(data interface{}, jsonPath string) string {
+	segments, err := parsePathSegments(jsonPath)
+	if err != nil {
+		return ""
+	}
+
+	current := []location{{}}
+	for _, segment := range segments {
+		next, err := stepLocations(data, current, segment)
+		if err != nil {
+			return ""
+		}
+
+		if len(next) == 0 {
+			if segment.kind != selectKeys || segment.recursive {
+				return ""
+			}
+			return missingKeyMessage(data, current, segment.keys)
+		}
+		current = next
+	}
+	return ""
+}
+
+// missingKeyMessage describes the first of keys missing from the objects at locations
+func missingKeyMessage(data interface{}, locations []location, keys []string) string {
+	available := map[string]bool{}
+	for _, loc := range locations {
+		if object, ok := getAtObject(data, loc); ok {
+			for key := range object {
+				available[key] = true
+			}
+		}
+	}
+	if len(available) == 0 {
+		return ""
+	}
+
+	key := keys[0]
+	message := fmt.Sprintf("unknown key '%s' at %s", key, locations[0])
+	closest := closestKeys(key, available)
+	if len(closest) == 0 {
+		return message
+	}
+	for i, candidate := range closest {
+		closest[i] = "'" + candidate + "'"
+	}
+	return message + "; did you mean " + strings.Join(closest, " or ") + "?"
+}
+
+// getAtObject returns the object at a location
+func getAtObject(data interface{}, loc location) (map[string]interface{}, bool) {
+	node, _ := getAt(data, loc)
+	object, ok := node.(map[string]interface{})
+	return object, ok
+}
+
+// closestKeys returns the available keys within a small edit distance of key, nearest first
+func closestKeys(key string, available map[string]bool) []string {
+	limit := len([]rune(key))/3 + 1
+	type scored struct {
+		key      string
+		distance int
+	}
+	candidates := []scored{}
+	for candidate := range available {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(candidate))
+		if distance <= limit {
+			candidates = append(candidates, scored{candidate, distance})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].key < candidates[j].key
+	})
+
+	closest := []string{}
+	for i := 0; i < len(candidates) && i < maxKeySuggestions; i++ {
+		closest = append(closest, candidates[i].key)
+	}
+	return closest
+}
+
+// editDistance computes the Levenshtein distance between two strings, counting transposed
+// neighbours as a single edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
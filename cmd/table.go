@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	maxColumnWidth  int
+	maxStringLength int
+	maxDepth        int
+	noTruncate      bool
+	tableBorders    bool
+)
+
+// ellipsis marks text that was cut short to fit a width limit
+const ellipsis = "…"
+
+func init() {
+	resultFlags.IntVar(&maxColumnWidth, "max-col-width", 40, "Truncate table cells wider than this many characters (0 for no limit)")
+	resultFlags.IntVar(&maxStringLength, "max-string", 0, "Truncate values longer than this many characters in table, tree and diff output (0 for no limit)")
+	resultFlags.IntVar(&maxDepth, "max-depth", 0, "Collapse nesting below this depth in tree output and --flatten columns (0 for no limit)")
+	resultFlags.BoolVar(&noTruncate, "no-truncate", false, "Disable --max-col-width, --max-string and --max-depth")
+	resultFlags.BoolVar(&tableBorders, "borders", false, "Draw borders around table output")
+}
+
+// formatTable renders an array of objects as an aligned text table
+func formatTable(data interface{}) ([]byte, error) {
+	header, rows, err := tabulate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cells are shown on a single line, truncated to the string and column width limits
+	all := make([][]string, 0, len(rows)+1)
+	widths := make([]int, len(header))
+	for r, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if r > 0 {
+				cell = truncateText(cell, truncationLimit(maxStringLength))
+			}
+			cells[i] = truncateText(strings.NewReplacer("\n", " ", "\t", " ").Replace(cell), truncationLimit(maxColumnWidth))
+			if width := utf8.RuneCountInString(cells[i]); width > widths[i] {
+				widths[i] = width
+			}
+		}
+		all = append(all, cells)
+	}
+
+	var buf bytes.Buffer
+	separator := func() {
+		for _, width := range widths {
+			buf.WriteString("+" + strings.Repeat("-", width+2))
+		}
+		buf.WriteString("+\n")
+	}
+
+	if tableBorders {
+		separator()
+	}
+	for r, row := range all {
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case tableBorders:
+				buf.WriteString("| " + cell + padding + " ")
+			case i < len(row)-1:
+				buf.WriteString(cell + padding + "  ")
+			default:
+				// No trailing whitespace on the last column
+				buf.WriteString(cell)
+			}
+		}
+		if tableBorders {
+			buf.WriteString("|")
+		}
+		buf.WriteString("\n")
+		if tableBorders && r == 0 {
+			separator()
+		}
+	}
+	if tableBorders {
+		separator()
+	}
+
+	return buf.Bytes(), nil
+}
+
+// truncateText shortens text to at most limit characters, ending in an ellipsis when cut
+func truncateText(text string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + ellipsis
+}
+
+// truncationLimit returns a width or depth limit, or 0 when --no-truncate is set
+func truncationLimit(limit int) int {
+	if noTruncate {
+		return 0
+	}
+	return limit
+}
+
+// markdownEscaper keeps cell text from breaking out of a markdown table cell
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// formatMarkdown renders an array of objects as a GitHub-flavored markdown table
+func formatMarkdown(data interface{}) ([]byte, error) {
+	header, rows, err := tabulate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeRow := func(row []string) {
+		buf.WriteString("|")
+		for _, cell := range row {
+			buf.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+		}
+		buf.WriteString("\n")
+	}
+
+	writeRow(header)
+	buf.WriteString("|")
+	for range header {
+		buf.WriteString(" --- |")
+	}
+	buf.WriteString("\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return buf.Bytes(), nil
+}
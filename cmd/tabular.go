@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var (
+	outputColumns []string
+	flattenNested bool
+)
+
+func init() {
+	resultFlags.StringSliceVar(&outputColumns, "columns", nil, "Columns to include, in order, for tabular output formats")
+	resultFlags.BoolVar(&flattenNested, "flatten", false, "Dot-join nested keys into separate columns for tabular output formats")
+}
+
+// tabulate converts an array of objects (or a single object) into a header and rows of cell text.
+// Arrays of scalars become a single "value" column.
+func tabulate(data interface{}) ([]string, [][]string, error) {
+	header, records, err := tabulateRecords(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		row := make([]string, len(header))
+		for j, column := range header {
+			row[j] = cellText(record[column])
+		}
+		rows[i] = row
+	}
+	return header, rows, nil
+}
+
+// tabulateRecords normalizes a result into objects, flattened with --flatten, and chooses
+// the columns: --columns when given, otherwise every key in sorted order
+func tabulateRecords(data interface{}) ([]string, []map[string]interface{}, error) {
+	var items []interface{}
+	switch v := data.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		items = []interface{}{v}
+	default:
+		return nil, nil, fmt.Errorf("tabular output requires an array or object, got %s", jsonTypeName(data))
+	}
+
+	records := make([]map[string]interface{}, len(items))
+	seen := map[string]bool{}
+	header := []string{}
+	for i, item := range items {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			record = map[string]interface{}{"value": item}
+		}
+		if flattenNested {
+			var err error
+			if record, err = flattenObject(record); err != nil {
+				return nil, nil, err
+			}
+		}
+		records[i] = record
+
+		for key := range record {
+			if !seen[key] {
+				seen[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+
+	// Maps carry no order, so default to sorted columns for stable output
+	sort.Strings(header)
+	if len(outputColumns) > 0 {
+		header = outputColumns
+	}
+	return header, records, nil
+}
+
+// flattenObject joins nested object keys with '.' and array indices with brackets.
+// Values nested deeper than --max-depth are kept whole in a single column.
+func flattenObject(object map[string]interface{}) (map[string]interface{}, error) {
+	return flattenWith(object, ".")
+}
+
+// flattenWith flattens an object or array into a single-level object, joining nested object keys with
+// separator and array indices with brackets, and stopping at --max-depth. Keys that already contain
+// the separator can produce the same flattened key as a nested member, which is an error.
+func flattenWith(value interface{}, separator string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	sources := make(map[string]location)
+	depthLimit := truncationLimit(maxDepth)
+	var walk func(prefix string, loc location, value interface{}) error
+	walk = func(prefix string, loc location, value interface{}) error {
+		if depthLimit > 0 && len(loc) >= depthLimit {
+			return setFlattened(result, sources, prefix, loc, value)
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if len(v) == 0 && len(loc) > 0 {
+				return setFlattened(result, sources, prefix, loc, v)
+			}
+			for _, child := range childLocations(v, loc) {
+				key := child[len(child)-1].(string)
+				flatKey := key
+				if prefix != "" {
+					flatKey = prefix + separator + key
+				}
+				if err := walk(flatKey, child, v[key]); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			if len(v) == 0 && len(loc) > 0 {
+				return setFlattened(result, sources, prefix, loc, v)
+			}
+			for i, child := range v {
+				if err := walk(prefix+"["+strconv.Itoa(i)+"]", appendLocation(loc, i), child); err != nil {
+					return err
+				}
+			}
+		default:
+			return setFlattened(result, sources, prefix, loc, v)
+		}
+		return nil
+	}
+
+	if err := walk("", location{}, value); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// setFlattened stores one flattened value, failing if another member already produced its key
+func setFlattened(result map[string]interface{}, sources map[string]location, key string, loc location, value interface{}) error {
+	if other, exists := sources[key]; exists {
+		return fmt.Errorf("flattened key %q comes from both %s and %s", key, other, loc)
+	}
+	sources[key] = loc
+	result[key] = value
+	return nil
+}
+
+// cellText renders a value as the text of a single table cell
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		// Numbers, booleans and nested values use their compact JSON form
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(bytes)
+	}
+}
+
+// isScalar reports whether a decoded value is neither an object nor an array
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+// isObject reports whether a decoded value is an object
+func isObject(value interface{}) bool {
+	_, ok := value.(map[string]interface{})
+	return ok
+}
+
+// jsonTypeName returns the JSON type name of a decoded value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+var (
+	outputTemplate     string
+	outputTemplateFile string
+)
+
+func init() {
+	resultFlags.StringVar(&outputTemplate, "template", "", "Go text/template (with sprig functions) applied to each result")
+	resultFlags.StringVar(&outputTemplateFile, "template-file", "", "File containing the output template")
+}
+
+// templateRequested reports whether results should be rendered through a template
+func templateRequested() bool {
+	return outputTemplate != "" || outputTemplateFile != ""
+}
+
+// formatTemplate renders the result through the output template. The main template runs once per
+// element when the result is an array; optional "header" and "footer" templates run once around
+// them with all results as dot, and the results function returns all results from any template.
+func formatTemplate(data interface{}) ([]byte, error) {
+	text := outputTemplate
+	if outputTemplateFile != "" {
+		content, err := os.ReadFile(outputTemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading template file: %v", err)
+		}
+		// Template files usually end in a newline, which the separator between results already provides
+		text = strings.TrimSuffix(string(content), "\n")
+	}
+
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+
+	funcs := sprig.TxtFuncMap()
+	funcs["results"] = func() []interface{} { return items }
+	tmpl, err := template.New("output").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if header := tmpl.Lookup("header"); header != nil {
+		if err := header.Execute(&buf, items); err != nil {
+			return nil, fmt.Errorf("template error: %v", err)
+		}
+	}
+
+	// Templates made only of header/footer definitions render nothing per result
+	perResult := !onlyDefinitions(tmpl)
+	if perResult {
+		for i, item := range items {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			if err := tmpl.Execute(&buf, item); err != nil {
+				return nil, fmt.Errorf("template error: %v", err)
+			}
+		}
+	}
+
+	if footer := tmpl.Lookup("footer"); footer != nil {
+		if perResult && len(items) > 0 && !strings.HasSuffix(buf.String(), "\n") {
+			buf.WriteString("\n")
+		}
+		if err := footer.Execute(&buf, items); err != nil {
+			return nil, fmt.Errorf("template error: %v", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// onlyDefinitions reports whether a template's main body holds nothing but {{define}} blocks
+// and the whitespace between them
+func onlyDefinitions(tmpl *template.Template) bool {
+	if len(tmpl.Templates()) < 2 {
+		return false
+	}
+	if tmpl.Tree == nil {
+		return true
+	}
+	for _, node := range tmpl.Tree.Root.Nodes {
+		text, ok := node.(*parse.TextNode)
+		if !ok || strings.TrimSpace(string(text.Text)) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+var (
+	httpTimeout    time.Duration
+	httpRetries    int
+	httpProxy      string
+	httpInsecure   bool
+	httpCACert     string
+	httpClientCert string
+	httpClientKey  string
+)
+
+// retryBaseDelay is the wait before the first retry; it doubles on each attempt
+const retryBaseDelay = 500 * time.Millisecond
+
+func init() {
+	readCmd.Flags().DurationVar(&httpTimeout, "timeout", 30*time.Second, "Timeout for fetching URLs, or for connecting to streams (0 for none)")
+	readCmd.Flags().IntVar(&httpRetries, "retries", 0, "Retry failed URL fetches this many times with exponential backoff")
+	readCmd.Flags().StringVar(&httpProxy, "proxy", "", "Proxy URL for remote sources (defaults to HTTP_PROXY/HTTPS_PROXY)")
+	readCmd.Flags().BoolVar(&httpInsecure, "insecure", false, "Skip TLS certificate verification for remote sources")
+	readCmd.Flags().StringVar(&httpCACert, "cacert", "", "PEM file of CA certificates to trust for remote sources")
+	readCmd.Flags().StringVar(&httpClientCert, "client-cert", "", "PEM client certificate for remote sources")
+	readCmd.Flags().StringVar(&httpClientKey, "client-key", "", "PEM private key for --client-cert")
+}
+
+// newTLSConfig builds the TLS configuration from the --insecure, --cacert and client certificate flags
+func newTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: httpInsecure}
+
+	if httpCACert != "" {
+		pem, err := os.ReadFile(httpCACert)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CA certificate: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Error reading CA certificate: no certificates found in %s", httpCACert)
+		}
+		config.RootCAs = pool
+	}
+
+	if httpClientCert != "" || httpClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(httpClientCert, httpClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// proxyFunc returns the proxy selector for --proxy, falling back to the environment
+func proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if httpProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyURL, err := url.Parse(httpProxy)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing proxy URL: %v", err)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// newTransport builds an HTTP transport honoring the proxy and TLS flags.
+// --timeout bounds connection setup so it also applies to long-lived streams.
+func newTransport() (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	transport.DialContext = (&net.Dialer{Timeout: httpTimeout}).DialContext
+	transport.TLSHandshakeTimeout = httpTimeout
+	return transport, nil
+}
+
+// newHTTPClient builds a client for one-shot fetches, bounded end to end by --timeout
+func newHTTPClient() (*http.Client, error) {
+	transport, err := newTransport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: httpTimeout}, nil
+}
+
+// doWithRetries sends a request, retrying network errors and retryable statuses with exponential backoff
+func doWithRetries(client *http.Client, req *http.Request) (*http.Response, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= httpRetries || (err == nil && !isRetryableStatus(resp.StatusCode)) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// formatTree renders a result as an indented tree with a type summary for each container
+func formatTree(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	writeTreeNode(&buf, "", "", "$", data, 0)
+	return buf.Bytes(), nil
+}
+
+// writeTreeNode writes one node line and then its children, drawing branches with box characters.
+// Containers at --max-depth are shown collapsed with an ellipsis.
+func writeTreeNode(buf *bytes.Buffer, prefix string, branch string, key string, value interface{}, depth int) {
+	summary := treeSummary(value)
+	if depthLimit := truncationLimit(maxDepth); depthLimit > 0 && depth >= depthLimit {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			buf.WriteString(prefix + branch + key + summary + " " + ellipsis + "\n")
+			return
+		}
+	}
+	buf.WriteString(prefix + branch + key + summary + "\n")
+
+	// Children are indented under this node's branch
+	switch branch {
+	case "├── ":
+		prefix += "│   "
+	case "└── ":
+		prefix += "    "
+	}
+
+	var keys []string
+	var children []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			children = append(children, v[k])
+		}
+	case []interface{}:
+		for i, item := range v {
+			keys = append(keys, "["+strconv.Itoa(i)+"]")
+			children = append(children, item)
+		}
+	}
+
+	for i, child := range children {
+		childBranch := "├── "
+		if i == len(children)-1 {
+			childBranch = "└── "
+		}
+		writeTreeNode(buf, prefix, childBranch, keys[i], child, depth+1)
+	}
+}
+
+// treeSummary describes a node: its size for containers, or its value for scalars
+func treeSummary(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf(" (object, %s)", plural(len(v), "key"))
+	case []interface{}:
+		return fmt.Sprintf(" (array, %s)", plural(len(v), "item"))
+	case string:
+		v = truncateText(v, truncationLimit(maxStringLength))
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ": " + v
+		}
+		return ": " + string(encoded)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ": " + fmt.Sprint(v)
+		}
+		return ": " + string(encoded)
+	}
+}
+
+// plural formats a count with a singular or plural noun
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(count) + " " + noun + "s"
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// typeCmd represents the type command
+var typeCmd = &cobra.Command{
+	Use:   "type [jsonpath]",
+	Short: "Print the JSON type of each match",
+	Long: `Print the JSON type of the document, or of each value a path matches, one per line:
+string, number, boolean, object, array or null.
+
+  $ [ "$(mycli type -f config.json '$.servers')" = array ] && mycli read -f config.json '$.servers[0]'`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+		matches, err := typeMatches(jsonData, args)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			fmt.Fprintln(output, jsonTypeName(match))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(typeCmd)
+
+	typeCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	typeCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+}
+
+// typeMatches returns the values whose types are printed. JSONPaths are resolved with the
+// locator so object members come in key order rather than in map order.
+func typeMatches(jsonData interface{}, args []string) ([]interface{}, error) {
+	if len(args) == 1 && queryLanguage == "jsonpath" {
+		jsonPath, err := expandQuery(queryLanguage, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if locations, err := locateJSONPath(jsonData, jsonPath); err == nil {
+			if len(locations) == 0 && !isMultiMatchPath(jsonPath) {
+				return nil, jsonPathError(jsonPath, errNoMatch)
+			}
+			matches := make([]interface{}, len(locations))
+			for i, loc := range locations {
+				matches[i], _ = getAt(jsonData, loc)
+			}
+			return matches, nil
+		}
+	}
+
+	// Other languages, and paths the locator cannot follow, go through the query engine;
+	// a path that can match several values yields them as an array of matches
+	value, err := queryArgument(jsonData, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 && queryLanguage == "jsonpath" && isMultiMatchPath(args[0]) {
+		matches, _ := value.([]interface{})
+		return matches, nil
+	}
+	return []interface{}{value}, nil
+}
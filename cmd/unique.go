@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var uniqueBy string
+
+// uniqueCmd represents the unique command
+var uniqueCmd = &cobra.Command{
+	Use:   "unique [jsonpath]",
+	Short: "Remove repeated values from an array",
+	Long: `Remove repeated values from the array at a path, or the whole document, keeping the
+first occurrence of each in its original position. With --by, items are compared by the
+value that path selects in each rather than as a whole:
+
+  $ mycli unique -f events.json '$[*].user.id'
+  $ mycli unique -f events.json --by '$.user.id'`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: jsonPathCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonData, err := loadDocumentOrStdin()
+		if err != nil {
+			return err
+		}
+		value, err := queryArgument(jsonData, args)
+		if err != nil {
+			return err
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("unique requires an array, got %s", jsonTypeName(value))
+		}
+
+		byQuery, err := compileQuery(queryLanguage, uniqueBy, nil, true)
+		if err != nil {
+			return err
+		}
+		seen := map[string]bool{}
+		unique := []interface{}{}
+		for _, item := range items {
+			key, err := byQuery(item)
+			if err != nil {
+				return err
+			}
+			// Objects marshal with sorted keys, so equal values encode identically
+			encoded, err := json.Marshal(key)
+			if err != nil {
+				return fmt.Errorf("Error comparing values: %v", err)
+			}
+			if !seen[string(encoded)] {
+				seen[string(encoded)] = true
+				unique = append(unique, item)
+			}
+		}
+		return prettyPrintJSON(unique)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uniqueCmd)
+	addResultFlags(uniqueCmd)
+
+	uniqueCmd.Flags().StringVarP(&filePath, "file", "f", "", "File to read (defaults to JSON on stdin)")
+	uniqueCmd.RegisterFlagCompletionFunc("file", fileCompletion)
+	uniqueCmd.Flags().StringVar(&uniqueBy, "by", "", "Path, within each item, of the value to compare items by")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// isWebSocketURL reports whether the --file value refers to a WebSocket endpoint
+func isWebSocketURL(path string) bool {
+	return strings.HasPrefix(path, "ws://") || strings.HasPrefix(path, "wss://")
+}
+
+// readWebSocket connects to a WebSocket endpoint and passes each text frame to the handler
+func readWebSocket(url string, handle documentHandler) error {
+	ctx, cancel := streamContext()
+	defer cancel()
+
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		return err
+	}
+	proxy, err := proxyFunc()
+	if err != nil {
+		return err
+	}
+	dialer := &websocket.Dialer{
+		Proxy:            proxy,
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: httpTimeout,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("Error connecting to WebSocket: %v", err)
+	}
+	defer conn.Close()
+
+	// Unblock ReadMessage when interrupted or --duration elapses
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for received := 0; !streamLimitReached(received); {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return fmt.Errorf("Error receiving message: %v", err)
+		}
+		if messageType != websocket.TextMessage {
+			// Only text frames carry JSON
+			continue
+		}
+		received++
+		handleStreamMessage(data, handle)
+	}
+	return nil
+}
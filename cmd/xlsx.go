@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var xlsxSheet string
+
+func init() {
+	readCmd.Flags().StringVar(&xlsxSheet, "sheet", "", "Worksheet to read from an .xlsx file (defaults to the first sheet)")
+}
+
+// parseXLSX decodes a worksheet into an array of objects keyed by the header row.
+// Numeric and boolean cells keep their type; everything else is read as a string.
+func parseXLSX(data []byte) (interface{}, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Error opening workbook: %v", err)
+	}
+	defer f.Close()
+
+	sheet := xlsxSheet
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading sheet %s: %v", sheet, err)
+	}
+
+	result := []interface{}{}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	headers := rows[0]
+	for r, row := range rows[1:] {
+		record := make(map[string]interface{}, len(headers))
+		for c, header := range headers {
+			if header == "" {
+				continue
+			}
+			if c >= len(row) {
+				record[header] = nil
+				continue
+			}
+
+			// Row 1 holds the headers, so data rows start at row 2
+			cell, err := excelize.CoordinatesToCellName(c+1, r+2)
+			if err != nil {
+				return nil, err
+			}
+			record[header] = xlsxCellValue(f, sheet, cell, row[c])
+		}
+		result = append(result, record)
+	}
+
+	return result, nil
+}
+
+// xlsxCellValue converts a cell's formatted text to a typed value based on the cell type
+func xlsxCellValue(f *excelize.File, sheet string, cell string, text string) interface{} {
+	if text == "" {
+		return nil
+	}
+
+	cellType, err := f.GetCellType(sheet, cell)
+	if err != nil {
+		return text
+	}
+
+	switch cellType {
+	case excelize.CellTypeBool:
+		return text == "TRUE" || text == "1"
+	case excelize.CellTypeNumber, excelize.CellTypeUnset:
+		// Use the raw value so number formats such as currency do not get in the way
+		raw, err := f.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return text
+		}
+		if number, err := strconv.ParseFloat(raw, 64); err == nil {
+			return number
+		}
+	}
+	return text
+}
+
+// xlsxOutputSheet names the worksheet written by the xlsx output format
+const xlsxOutputSheet = "Results"
+
+// formatXLSX writes an array of objects to a workbook with a bold header row and typed cells
+func formatXLSX(data interface{}) ([]byte, error) {
+	header, records, err := tabulateRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName(f.GetSheetName(0), xlsxOutputSheet); err != nil {
+		return nil, err
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	row := make([]interface{}, len(header))
+	for i, column := range header {
+		row[i] = column
+	}
+	if err := f.SetSheetRow(xlsxOutputSheet, "A1", &row); err != nil {
+		return nil, err
+	}
+	if len(header) > 0 {
+		last, _ := excelize.CoordinatesToCellName(len(header), 1)
+		if err := f.SetCellStyle(xlsxOutputSheet, "A1", last, bold); err != nil {
+			return nil, err
+		}
+	}
+
+	for r, record := range records {
+		row := make([]interface{}, len(header))
+		for i, column := range header {
+			switch value := record[column].(type) {
+			case float64, bool, string, nil:
+				row[i] = value
+			default:
+				// Nested values are stored as their compact JSON text
+				row[i] = cellText(value)
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, r+2)
+		if err := f.SetSheetRow(xlsxOutputSheet, cell, &row); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}